@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/handlers/message"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/handlers/presence"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/handlers/rooms"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/handlers/user"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/handlers/websocket"
@@ -22,7 +24,8 @@ import (
 func Run(cfg *config.Config) {
 	services := gateway.MustNew(cfg)
 	defer services.Close()
-	r := AddHandlers(cfg, services)
+	ws := websocket.New(cfg, services)
+	r := AddHandlers(cfg, services, ws)
 	server := &http.Server{
 		Addr:         cfg.HTTP.Host + ":" + cfg.HTTP.Port,
 		Handler:      r,
@@ -34,10 +37,10 @@ func Run(cfg *config.Config) {
 	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
 	go serve(cfg, services, server)
 	<-done
-	Shutdown(cfg, services, server)
+	Shutdown(cfg, services, server, ws)
 }
 
-func AddHandlers(cfg *config.Config, services *gateway.Services) *chi.Mux {
+func AddHandlers(cfg *config.Config, services *gateway.Services, ws *websocket.WS) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
 	r.Use(mw.CORS)
@@ -58,11 +61,32 @@ func AddHandlers(cfg *config.Config, services *gateway.Services) *chi.Mux {
 			r.Post("/create-room", rooms.Create(services))
 			r.Put("/invite", rooms.Invite(services))
 			r.Put("/join", rooms.Join(services))
+			r.Post("/direct", rooms.Direct(services))
 			r.Get("/rooms", rooms.UserIn(services))
 			r.Get(fmt.Sprintf("/messages/{%s}", message.URLParam), message.Get(services))
+			r.Post(fmt.Sprintf("/messages/{%s}", message.URLParam), message.Send(services))
+			r.Get(
+				fmt.Sprintf("/messages/{%s}/thread/{%s}", message.URLParam, message.URLParamMessageID),
+				message.GetThread(services),
+			)
+			r.Put("/read", message.MarkRead(services))
+			r.Put("/edit", message.Edit(services))
+			r.Put("/delete", message.Delete(services))
+			r.Put("/react", message.AddReaction(services))
+			r.Put("/unreact", message.RemoveReaction(services))
+			r.Get("/presence", presence.Get(services))
+			r.Post("/events/ticket", ws.Ticket())
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(mw.Admin(cfg.Admin.Token))
+			r.Get("/connections", ws.Connections())
+			r.Delete(fmt.Sprintf("/users/{%s}/connections", websocket.URLParamUID), ws.DisconnectUser())
+			r.Handle("/debug/vars", expvar.Handler())
 		})
 	})
-	r.HandleFunc("/ws", websocket.Connector(cfg, services))
+	r.HandleFunc("/ws", ws.Connector())
+	r.Get("/events", ws.Events())
+	r.Get("/poll", ws.Poll())
 	return r
 }
 
@@ -79,12 +103,12 @@ func serve(cfg *config.Config, services *gateway.Services, server *http.Server)
 	}
 }
 
-func Shutdown(cfg *config.Config, services *gateway.Services, server *http.Server) {
+func Shutdown(cfg *config.Config, services *gateway.Services, server *http.Server, ws *websocket.WS) {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 	services.Log.Info("shutting down server...")
 	grace := true
-	if err := websocket.Shutdown(shutdownCtx); err != nil {
+	if err := ws.Shutdown(shutdownCtx); err != nil {
 		services.Log.Error(
 			"websocket shutdown error",
 			"error", err,
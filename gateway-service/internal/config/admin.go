@@ -0,0 +1,5 @@
+package config
+
+type Admin struct {
+	Token string
+}
@@ -2,20 +2,32 @@ package config
 
 import "time"
 
+const (
+	FanOutGroup     = "group"
+	FanOutBroadcast = "broadcast"
+)
+
 type Kafka struct {
-	Brokers     []string      `yaml:"brokers"`
-	GroupID     string        `yaml:"group_id"`
-	Topic       string        `yaml:"topic"`
-	WorkerCount int           `yaml:"worker_count"`
-	Timeout     time.Duration `yaml:"timeout"`
+	Brokers         []string      `yaml:"brokers"`
+	GroupID         string        `yaml:"group_id"`
+	Topic           string        `yaml:"topic"`
+	EventsTopic     string        `yaml:"events_topic"`
+	UserEventsTopic string        `yaml:"user_events_topic"`
+	FanOut          string        `yaml:"fan_out"`
+	InstanceID      string        `yaml:"instance_id"`
+	WorkerCount     int           `yaml:"worker_count"`
+	Timeout         time.Duration `yaml:"timeout"`
 }
 
 func DefaultKafka() Kafka {
 	return Kafka{
-		Brokers:     []string{"kafka:9092"},
-		Topic:       "messages",
-		GroupID:     "my-consumer",
-		WorkerCount: 50,
-		Timeout:     2 * time.Second,
+		Brokers:         []string{"kafka:9092"},
+		Topic:           "messages",
+		EventsTopic:     "events",
+		UserEventsTopic: "user-events",
+		GroupID:         "my-consumer",
+		FanOut:          FanOutBroadcast,
+		WorkerCount:     50,
+		Timeout:         2 * time.Second,
 	}
-}	
+}
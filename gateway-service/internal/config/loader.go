@@ -1,8 +1,13 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/P3rCh1/chat-server/gateway-service/pkg/config"
 	"github.com/P3rCh1/chat-server/gateway-service/pkg/logger"
+	"github.com/google/uuid"
 )
 
 type Config struct {
@@ -11,9 +16,78 @@ type Config struct {
 	Services  Services  `yaml:"services"`
 	LogLVL    string    `yaml:"log_level"`
 	Kafka     Kafka     `yaml:"kafka"`
+	Redis     Redis     `yaml:"redis"`
+	Presence  Presence  `yaml:"presence"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Admin     Admin     `yaml:"admin"`
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.Kafka.FanOut != FanOutGroup && cfg.Kafka.FanOut != FanOutBroadcast {
+		return fmt.Errorf("unknown kafka fan_out mode %q", cfg.Kafka.FanOut)
+	}
+	if cfg.Kafka.WorkerCount <= 0 {
+		return errors.New("kafka worker_count must be positive")
+	}
+	if cfg.Websocket.OverflowPolicy != OverflowDropOldest && cfg.Websocket.OverflowPolicy != OverflowDisconnect {
+		return fmt.Errorf("unknown websocket overflow_policy %q", cfg.Websocket.OverflowPolicy)
+	}
+	if cfg.Websocket.SendQueueSize <= 0 {
+		return errors.New("websocket send_queue_size must be positive")
+	}
+	if cfg.Websocket.MaxBackfill > cfg.Websocket.SendQueueSize {
+		return errors.New("websocket max_backfill must not exceed send_queue_size")
+	}
+	if cfg.Websocket.PollIdleTimeout <= cfg.Websocket.PollTimeout {
+		return errors.New("websocket poll_idle_timeout must be longer than poll_timeout")
+	}
+	if cfg.Websocket.TokenRecheck <= 0 {
+		return errors.New("websocket token_recheck must be positive")
+	}
+	if cfg.Websocket.TicketTTL <= 0 {
+		return errors.New("websocket ticket_ttl must be positive")
+	}
+	if cfg.Websocket.ReconnectJitter < 0 {
+		return errors.New("websocket reconnect_jitter must not be negative")
+	}
+	if cfg.Websocket.MaxConnsPerUser < 0 {
+		return errors.New("websocket max_conns_per_user must not be negative")
+	}
+	if cfg.Websocket.RetryQueueSize < 0 {
+		return errors.New("websocket retry_queue_size must not be negative")
+	}
+	if cfg.Websocket.RetryQueueSize > 0 {
+		if cfg.Websocket.RetryAttempts <= 0 || cfg.Websocket.RetryBackoff <= 0 {
+			return errors.New("websocket retry_attempts and retry_backoff must be positive")
+		}
+		if cfg.Websocket.RetryMaxBackoff < cfg.Websocket.RetryBackoff {
+			return errors.New("websocket retry_max_backoff must not be shorter than retry_backoff")
+		}
+	}
+	if cfg.Redis.Password == "" {
+		return errors.New("redis password is required")
+	}
+	if cfg.Presence.TTL <= cfg.Websocket.PingPeriod {
+		return errors.New("presence ttl must be longer than websocket ping_period")
+	}
+	if cfg.RateLimit.UserRate <= 0 {
+		return errors.New("rate_limit user_rate must be positive")
+	}
+	if cfg.RateLimit.UserBurst <= 0 {
+		return errors.New("rate_limit user_burst must be positive")
+	}
+	if cfg.RateLimit.ConnRate <= 0 {
+		return errors.New("rate_limit conn_rate must be positive")
+	}
+	if cfg.RateLimit.ConnBurst <= 0 {
+		return errors.New("rate_limit conn_burst must be positive")
+	}
+	if cfg.RateLimit.MaxViolations <= 0 {
+		return errors.New("rate_limit max_violations must be positive")
+	}
+	if cfg.RateLimit.ViolationWindow <= 0 {
+		return errors.New("rate_limit violation_window must be positive")
+	}
 	return nil
 }
 
@@ -23,12 +97,27 @@ func Default() *Config {
 		Websocket: DefaultWebsocket(),
 		Services:  DefaultServices(),
 		Kafka:     DefaultKafka(),
+		Redis:     DefaultRedis(),
+		Presence:  DefaultPresence(),
+		RateLimit: DefaultRateLimit(),
 		LogLVL:    logger.InfoLVL,
 	}
 }
 
 func MustLoad() *Config {
 	cfg := Default()
+	cfg.Redis.Password = os.Getenv("REDIS_PASSWORD")
+	cfg.Admin.Token = os.Getenv("ADMIN_TOKEN")
 	config.MustLoad(cfg)
+	if cfg.Kafka.InstanceID == "" {
+		cfg.Kafka.InstanceID = instanceID()
+	}
 	return cfg
 }
+
+func instanceID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+type Redis struct {
+	Addr     string `yaml:"addr"`
+	DB       int    `yaml:"db"`
+	Password string
+}
+
+func DefaultRedis() Redis {
+	return Redis{
+		Addr: "redis:6379",
+	}
+}
+
+type Presence struct {
+	TTL time.Duration `yaml:"ttl"`
+}
+
+func DefaultPresence() Presence {
+	return Presence{
+		TTL: 2 * time.Minute,
+	}
+}
+
+type RateLimit struct {
+	UserRate        float64       `yaml:"user_rate"`
+	UserBurst       int           `yaml:"user_burst"`
+	ConnRate        float64       `yaml:"conn_rate"`
+	ConnBurst       int           `yaml:"conn_burst"`
+	MaxViolations   int           `yaml:"max_violations"`
+	ViolationWindow time.Duration `yaml:"violation_window"`
+}
+
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		UserRate:        10,
+		UserBurst:       30,
+		ConnRate:        5,
+		ConnBurst:       20,
+		MaxViolations:   20,
+		ViolationWindow: 10 * time.Second,
+	}
+}
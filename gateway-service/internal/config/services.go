@@ -11,10 +11,12 @@ type Services struct {
 }
 
 type TimeoutsServices struct {
-	Session time.Duration `yaml:"session"`
-	User    time.Duration `yaml:"user"`
-	Rooms   time.Duration `yaml:"rooms"`
-	Message time.Duration `yaml:"message"`
+	Session   time.Duration `yaml:"session"`
+	User      time.Duration `yaml:"user"`
+	Rooms     time.Duration `yaml:"rooms"`
+	Message   time.Duration `yaml:"message"`
+	Presence  time.Duration `yaml:"presence"`
+	RateLimit time.Duration `yaml:"rate_limit"`
 }
 
 func DefaultServices() Services {
@@ -24,10 +26,12 @@ func DefaultServices() Services {
 		RoomsAddr:   "rooms:50053",
 		MessageAddr: "message:50054",
 		Timeouts: TimeoutsServices{
-			Session: 3 * time.Second,
-			User:    3 * time.Second,
-			Rooms:   3 * time.Second,
-			Message: 3 * time.Second,
+			Session:   3 * time.Second,
+			User:      3 * time.Second,
+			Rooms:     3 * time.Second,
+			Message:   3 * time.Second,
+			Presence:  2 * time.Second,
+			RateLimit: time.Second,
 		},
 	}
 }
@@ -2,6 +2,11 @@ package config
 
 import "time"
 
+const (
+	OverflowDropOldest = "drop_oldest"
+	OverflowDisconnect = "disconnect"
+)
+
 type Websocket struct {
 	MsgMaxSize        int           `yaml:"msg_max_size"`
 	MsgMaxLength      int           `yaml:"msg_max_length"`
@@ -12,6 +17,22 @@ type Websocket struct {
 	PongWait          time.Duration `yaml:"pong_wait"`
 	PingPeriod        time.Duration `yaml:"ping_period"`
 	MaxFailedPings    int           `yaml:"max_failed_pings"`
+	SendQueueSize     int           `yaml:"send_queue_size"`
+	OverflowPolicy    string        `yaml:"overflow_policy"`
+	MaxBackfill       int           `yaml:"max_backfill"`
+	TypingTimeout     time.Duration `yaml:"typing_timeout"`
+	PollTimeout       time.Duration `yaml:"poll_timeout"`
+	PollIdleTimeout   time.Duration `yaml:"poll_idle_timeout"`
+	AuthTimeout       time.Duration `yaml:"auth_timeout"`
+	TokenExpiryNotice time.Duration `yaml:"token_expiry_notice"`
+	TokenRecheck      time.Duration `yaml:"token_recheck"`
+	TicketTTL         time.Duration `yaml:"ticket_ttl"`
+	ReconnectJitter   time.Duration `yaml:"reconnect_jitter"`
+	MaxConnsPerUser   int           `yaml:"max_conns_per_user"`
+	RetryQueueSize    int           `yaml:"retry_queue_size"`
+	RetryAttempts     int           `yaml:"retry_attempts"`
+	RetryBackoff      time.Duration `yaml:"retry_backoff"`
+	RetryMaxBackoff   time.Duration `yaml:"retry_max_backoff"`
 	CheckOrigin       bool          `yaml:"check_origin"`
 	AllowedOrigins    []string      `yaml:"allowed_origins"`
 }
@@ -26,6 +47,22 @@ func DefaultWebsocket() Websocket {
 		PongWait:          60 * time.Second,
 		PingPeriod:        54 * time.Second,
 		MaxFailedPings:    3,
+		SendQueueSize:     1024,
+		OverflowPolicy:    OverflowDropOldest,
+		MaxBackfill:       1000,
+		TypingTimeout:     5 * time.Second,
+		PollTimeout:       25 * time.Second,
+		PollIdleTimeout:   60 * time.Second,
+		AuthTimeout:       10 * time.Second,
+		TokenExpiryNotice: time.Minute,
+		TokenRecheck:      5 * time.Minute,
+		TicketTTL:         30 * time.Second,
+		ReconnectJitter:   30 * time.Second,
+		MaxConnsPerUser:   10,
+		RetryQueueSize:    16,
+		RetryAttempts:     5,
+		RetryBackoff:      500 * time.Millisecond,
+		RetryMaxBackoff:   8 * time.Second,
 		EnableCompression: true,
 		CheckOrigin:       false,
 	}
@@ -0,0 +1,49 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
+	userpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/user"
+)
+
+// DirectRooms lists the direct rooms of uid with the profile of the peer in
+// each of them. Profiles are fetched at once; a room whose peer profile
+// couldn't be fetched is listed with just RoomID and UID.
+func (s *Services) DirectRooms(ctx context.Context, uid int64) ([]*models.DirectRoom, error) {
+	const op = "gateway.DirectRooms"
+	roomsCtx, cancel := context.WithTimeout(ctx, s.Timeouts.Rooms)
+	defer cancel()
+	resp, err := s.Rooms.Directs(roomsCtx, &roomspb.UserInRequest{UID: uid})
+	if err != nil {
+		return nil, err
+	}
+	userCtx, cancel := context.WithTimeout(ctx, s.Timeouts.User)
+	defer cancel()
+	directs := make([]*models.DirectRoom, len(resp.Directs))
+	wg := sync.WaitGroup{}
+	wg.Add(len(resp.Directs))
+	for i, d := range resp.Directs {
+		directs[i] = &models.DirectRoom{RoomID: d.RoomID, UID: d.PeerUID}
+		go func() {
+			defer wg.Done()
+			profile, err := s.User.Profile(userCtx, &userpb.ProfileRequest{UID: d.PeerUID})
+			if err != nil {
+				s.Log.Error(
+					op,
+					"error", err,
+					"uid", d.PeerUID,
+				)
+				return
+			}
+			directs[i].Username = profile.Username
+			directs[i].Email = profile.Email
+			createdAt := profile.CreatedAt.AsTime()
+			directs[i].CreatedAt = &createdAt
+		}()
+	}
+	wg.Wait()
+	return directs, nil
+}
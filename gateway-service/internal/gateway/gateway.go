@@ -9,11 +9,15 @@ import (
 
 	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/kafka"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/presence"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/ratelimit"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/ticket"
 	"github.com/P3rCh1/chat-server/gateway-service/pkg/logger"
 	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
 	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
 	sessionpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/session"
 	userpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/user"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -21,14 +25,21 @@ import (
 const ServicesCount = 4
 
 type Services struct {
-	Session  sessionpb.SessionClient
-	User     userpb.UserClient
-	Rooms    roomspb.RoomsClient
-	Message  msgpb.MessageServiceClient
-	Kafka    *kafka.Consumer
-	Log      *slog.Logger
-	Timeouts *config.TimeoutsServices
-	conns    []*grpc.ClientConn
+	Session    sessionpb.SessionClient
+	User       userpb.UserClient
+	Rooms      roomspb.RoomsClient
+	Message    msgpb.MessageServiceClient
+	Kafka      *kafka.Consumer
+	Events     *kafka.Consumer
+	UserEvents *kafka.Consumer
+	Producer   *kafka.Producer
+	Redis      *redis.Client
+	Presence   *presence.Tracker
+	Limiter    *ratelimit.Limiter
+	Tickets    *ticket.Store
+	Log        *slog.Logger
+	Timeouts   *config.TimeoutsServices
+	conns      []*grpc.ClientConn
 }
 
 func MustNew(cfg *config.Config) *Services {
@@ -74,6 +85,21 @@ func MustNew(cfg *config.Config) *Services {
 		}
 	}()
 	s.Kafka = kafka.NewConsumer(cfg.Kafka)
+	s.Events = kafka.NewEventsConsumer(cfg.Kafka)
+	s.UserEvents = kafka.NewUserEventsConsumer(cfg.Kafka)
+	s.Producer = kafka.NewProducer(cfg.Kafka, s.Log)
+	if err := s.connectRedis(&cfg.Redis); err != nil {
+		s.Log.Error(
+			"failed to connect redis",
+			"error", err,
+			"address", cfg.Redis.Addr,
+		)
+		ok.Store(false)
+	} else {
+		s.Presence = presence.New(s.Redis, &cfg.Presence)
+		s.Limiter = ratelimit.New(s.Redis, &cfg.RateLimit)
+		s.Tickets = ticket.New(s.Redis, cfg.Websocket.TicketTTL)
+	}
 	wg.Wait()
 	if !ok.Load() {
 		s.Close()
@@ -87,6 +113,35 @@ func (s *Services) Close() {
 	for _, v := range s.conns {
 		v.Close()
 	}
+	if s.Kafka != nil {
+		s.Kafka.Close()
+	}
+	if s.Events != nil {
+		s.Events.Close()
+	}
+	if s.UserEvents != nil {
+		s.UserEvents.Close()
+	}
+	if s.Producer != nil {
+		s.Producer.Close()
+	}
+	if s.Redis != nil {
+		s.Redis.Close()
+	}
+}
+
+func (s *Services) connectRedis(cfg *config.Redis) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		DB:       cfg.DB,
+		Password: cfg.Password,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return err
+	}
+	s.Redis = client
+	return nil
 }
 
 func (s *Services) AddConn(log *slog.Logger, addr string) *grpc.ClientConn {
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/middleware"
@@ -14,9 +15,13 @@ import (
 	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
 	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
 )
 
-const URLParam = "roomID"
+const (
+	URLParam          = "roomID"
+	URLParamMessageID = "messageID"
+)
 
 func Get(s *gateway.Services) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -54,38 +59,281 @@ func Get(s *gateway.Services) http.HandlerFunc {
 	}
 }
 
+func GetThread(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.GetThreadRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		var err error
+		req.RoomID, err = strconv.ParseInt(chi.URLParam(r, URLParam), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid room id", http.StatusBadRequest)
+			return
+		}
+		req.ThreadRoot, err = strconv.ParseInt(chi.URLParam(r, URLParamMessageID), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid message id", http.StatusBadRequest)
+			return
+		}
+		uid := r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		isMember, err := s.Rooms.IsMember(ctx, &roomspb.IsMemberRequest{UID: uid, RoomID: req.RoomID})
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
+			return
+		}
+		if !isMember.IsMember {
+			http.Error(w, "not room member", http.StatusForbidden)
+			return
+		}
+		msgs, err := s.Message.GetThread(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		writeMessages(w, msgs.Messages)
+	}
+}
+
+func Send(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.SendRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		var err error
+		req.RoomID, err = strconv.ParseInt(chi.URLParam(r, URLParam), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid room id", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		req.Type = "message"
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		isMember, err := s.Rooms.IsMember(ctx, &roomspb.IsMemberRequest{UID: req.UID, RoomID: req.RoomID})
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
+			return
+		}
+		if !isMember.IsMember {
+			http.Error(w, "not room member", http.StatusForbidden)
+			return
+		}
+		resp, err := s.Message.Send(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		responses.SendJSON(w, http.StatusCreated, struct {
+			MessageID int64     `json:"MessageID"`
+			Timestamp time.Time `json:"Timestamp"`
+			ClientID  string    `json:"ClientID,omitempty"`
+		}{
+			MessageID: resp.ID,
+			Timestamp: resp.Timestamp.AsTime(),
+			ClientID:  req.ClientID,
+		})
+	}
+}
+
+func MarkRead(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.MarkReadRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		isMember, err := s.Rooms.IsMember(ctx, &roomspb.IsMemberRequest{UID: req.UID, RoomID: req.RoomID})
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
+			return
+		}
+		if !isMember.IsMember {
+			http.Error(w, "not room member", http.StatusForbidden)
+			return
+		}
+		resp, err := s.Message.MarkRead(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, map[string]int64{
+			"RoomID":     req.RoomID,
+			"LastReadID": resp.LastReadID,
+		})
+	}
+}
+
+func Edit(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.EditRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		resp, err := s.Message.Edit(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, struct {
+			MessageID int64     `json:"MessageID"`
+			RoomID    int64     `json:"RoomID"`
+			EditedAt  time.Time `json:"EditedAt"`
+		}{
+			MessageID: req.MessageID,
+			RoomID:    resp.RoomID,
+			EditedAt:  resp.EditedAt.AsTime(),
+		})
+	}
+}
+
+func Delete(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.DeleteRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		resp, err := s.Message.Delete(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, struct {
+			MessageID int64     `json:"MessageID"`
+			RoomID    int64     `json:"RoomID"`
+			DeletedAt time.Time `json:"DeletedAt"`
+		}{
+			MessageID: req.MessageID,
+			RoomID:    resp.RoomID,
+			DeletedAt: resp.DeletedAt.AsTime(),
+		})
+	}
+}
+
+func AddReaction(s *gateway.Services) http.HandlerFunc {
+	return react(s, s.Message.AddReaction)
+}
+
+func RemoveReaction(s *gateway.Services) http.HandlerFunc {
+	return react(s, s.Message.RemoveReaction)
+}
+
+type reactFunc func(ctx context.Context, r *msgpb.ReactionRequest, opts ...grpc.CallOption) (*msgpb.ReactionResponse, error)
+
+func react(s *gateway.Services, apply reactFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := &msgpb.ReactionRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Message)
+		defer cancel()
+		resp, err := apply(ctx, req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, struct {
+			MessageID     int64  `json:"MessageID"`
+			RoomID        int64  `json:"RoomID"`
+			Emoji         string `json:"Emoji"`
+			ReactionCount int64  `json:"ReactionCount"`
+		}{
+			MessageID:     req.MessageID,
+			RoomID:        resp.RoomID,
+			Emoji:         req.Emoji,
+			ReactionCount: resp.Count,
+		})
+	}
+}
+
 func writeMessages(w io.Writer, messages []*msgpb.Message) error {
 	_, err := w.Write([]byte{'['})
 	if err != nil {
 		return err
 	}
-	for i := 0; i < len(messages)-1; i++ {
-		_, err := fmt.Fprintf(w, `{"ID":%d,"RoomID":%d,"UID":%d,"Type":%q,"Text":%q,"Timestamp":%q},`,
-			messages[i].ID,
-			messages[i].RoomID,
-			messages[i].UID,
-			messages[i].Type,
-			messages[i].Text,
-			messages[i].Timestamp.AsTime(),
-		)
+	for i, m := range messages {
+		if i > 0 {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		if err := writeMessage(w, m); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write([]byte{']', '\n'})
+	return err
+}
+
+func writeMessage(w io.Writer, m *msgpb.Message) error {
+	_, err := fmt.Fprintf(w, `{"ID":%d,"RoomID":%d,"UID":%d,"Type":%q,"Text":%q,"Timestamp":%q,"ClientID":%q`,
+		m.ID,
+		m.RoomID,
+		m.UID,
+		m.Type,
+		m.Text,
+		m.Timestamp.AsTime(),
+		m.ClientID,
+	)
+	if err != nil {
+		return err
+	}
+	if m.EditedAt != nil {
+		if _, err := fmt.Fprintf(w, `,"EditedAt":%q`, m.EditedAt.AsTime()); err != nil {
+			return err
+		}
+	}
+	if m.DeletedAt != nil {
+		if _, err := fmt.Fprintf(w, `,"DeletedAt":%q`, m.DeletedAt.AsTime()); err != nil {
+			return err
+		}
+	}
+	if m.ReplyTo != 0 {
+		if _, err := fmt.Fprintf(w, `,"ReplyTo":%d,"ThreadRoot":%d`, m.ReplyTo, m.ThreadRoot); err != nil {
+			return err
+		}
+	}
+	if m.ReplyCount > 0 {
+		_, err := fmt.Fprintf(w, `,"ReplyCount":%d,"LastReplyAt":%q`, m.ReplyCount, m.LastReplyAt.AsTime())
 		if err != nil {
 			return err
 		}
 	}
-	lastIdx := len(messages) - 1
-	if lastIdx >= 0 {
-		_, err = fmt.Fprintf(w, `{"ID":%d,"RoomID":%d,"UID":%d,"Type":%q,"Text":%q,"Timestamp":%q}`,
-			messages[len(messages)-1].ID,
-			messages[len(messages)-1].RoomID,
-			messages[len(messages)-1].UID,
-			messages[len(messages)-1].Type,
-			messages[len(messages)-1].Text,
-			messages[len(messages)-1].Timestamp.AsTime(),
-		)
+	if len(m.Reactions) > 0 {
+		reactions, err := json.Marshal(m.Reactions)
 		if err != nil {
 			return err
 		}
+		if _, err := fmt.Fprintf(w, `,"Reactions":%s`, reactions); err != nil {
+			return err
+		}
 	}
-	_, err = w.Write([]byte{']', '\n'})
+	_, err = w.Write([]byte{'}'})
 	return err
 }
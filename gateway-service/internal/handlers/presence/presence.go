@@ -0,0 +1,56 @@
+package presence
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+)
+
+const MaxUIDs = 100
+
+func Get(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		uids, err := parseUIDs(r.URL.Query().Get("uids"))
+		if err != nil {
+			http.Error(w, "invalid uids", http.StatusBadRequest)
+			return
+		}
+		if len(uids) > MaxUIDs {
+			http.Error(w, "too many uids", http.StatusBadRequest)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Presence)
+		defer cancel()
+		resp, err := s.Presence.Get(ctx, uids...)
+		if err != nil {
+			s.Log.Error(
+				"presence error",
+				"error", err,
+			)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, resp)
+	}
+}
+
+func parseUIDs(raw string) ([]int64, error) {
+	if raw == "" {
+		return nil, strconv.ErrSyntax
+	}
+	parts := strings.Split(raw, ",")
+	uids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		uid, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || uid <= 0 {
+			return nil, strconv.ErrSyntax
+		}
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
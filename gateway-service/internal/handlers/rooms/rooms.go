@@ -9,7 +9,9 @@ import (
 
 	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/middleware"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
 	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
 	"github.com/go-chi/chi/v5"
 )
@@ -79,6 +81,39 @@ func Invite(s *gateway.Services) http.HandlerFunc {
 	}
 }
 
+func Direct(s *gateway.Services) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := roomspb.DirectRequest{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		req.UID = r.Context().Value(middleware.UIDContextKey).(int64)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeouts.Rooms)
+		defer cancel()
+		resp, err := s.Rooms.GetOrCreateDirect(ctx, &req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
+			return
+		}
+		code := http.StatusOK
+		if resp.Created {
+			code = http.StatusCreated
+		}
+		responses.SendJSON(w, code, struct {
+			RoomID  int64 `json:"RoomID"`
+			UID     int64 `json:"UID"`
+			Created bool  `json:"Created"`
+		}{
+			RoomID:  resp.RoomID,
+			UID:     req.PeerUID,
+			Created: resp.Created,
+		})
+	}
+}
+
 func Get(s *gateway.Services) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer r.Body.Close()
@@ -100,12 +135,14 @@ func Get(s *gateway.Services) http.HandlerFunc {
 			Name       string    `json:"Name"`
 			CreatorUID int64     `json:"CreatorUID"`
 			IsPrivate  bool      `json:"IsPrivate"`
+			IsDirect   bool      `json:"IsDirect"`
 			CreatedAt  time.Time `json:"CreatedAt"`
 		}{
 			RoomID:     respGRPC.RoomID,
 			Name:       respGRPC.Name,
 			CreatorUID: respGRPC.CreatorUID,
 			IsPrivate:  respGRPC.IsPrivate,
+			IsDirect:   respGRPC.IsDirect,
 			CreatedAt:  respGRPC.CreatedAt.AsTime(),
 		}
 		responses.SendJSON(w, http.StatusOK, resp)
@@ -118,11 +155,38 @@ func UserIn(s *gateway.Services) http.HandlerFunc {
 		req := roomspb.UserInRequest{UID: r.Context().Value(middleware.UIDContextKey).(int64)}
 		ctx, cancel := context.WithTimeout(context.Background(), s.Timeouts.Rooms)
 		defer cancel()
-		resp, err := s.Rooms.UserIn(ctx, &req)
+		userIn, err := s.Rooms.UserIn(ctx, &req)
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
+			return
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), s.Timeouts.Message)
+		defer cancel()
+		unread, err := s.Message.Unread(ctx, &msgpb.UnreadRequest{UID: req.UID, RoomIDs: userIn.IDs})
+		if err != nil {
+			responses.GatewayGRPCErr(w, s.Log, "messages", err)
+			return
+		}
+		directs, err := s.DirectRooms(r.Context(), req.UID)
 		if err != nil {
 			responses.GatewayGRPCErr(w, s.Log, "rooms", err)
 			return
 		}
+		resp := struct {
+			IDs     []int64              `json:"IDs,omitempty"`
+			Directs []*models.DirectRoom `json:"Directs"`
+			Unread  map[int64]int64      `json:"Unread"`
+		}{
+			IDs:     models.WithoutDirects(userIn.IDs, directs),
+			Directs: directs,
+			Unread:  unread.Counts,
+		}
+		if resp.Directs == nil {
+			resp.Directs = []*models.DirectRoom{}
+		}
+		if resp.Unread == nil {
+			resp.Unread = make(map[int64]int64)
+		}
 		responses.SendJSON(w, http.StatusOK, resp)
 	}
 }
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+	"github.com/go-chi/chi/v5"
+)
+
+const URLParamUID = "UID"
+
+type connectionInfo struct {
+	ID          string    `json:"ID"`
+	UID         int64     `json:"UID"`
+	Transport   string    `json:"Transport"`
+	RoomIDs     []int64   `json:"RoomIDs"`
+	RemoteAddr  string    `json:"RemoteAddr"`
+	ConnectedAt time.Time `json:"ConnectedAt"`
+}
+
+type connectionsResponse struct {
+	Instance    string            `json:"Instance"`
+	Connections []*connectionInfo `json:"Connections"`
+}
+
+// Connections lists the live connections of this gateway instance, only
+// the given user's ones with ?uid=.
+func (ws *WS) Connections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var uid int64
+		if raw := r.URL.Query().Get("uid"); raw != "" {
+			var err error
+			if uid, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				http.Error(w, "invalid uid", http.StatusBadRequest)
+				return
+			}
+		}
+		resp := connectionsResponse{
+			Instance:    ws.instanceID,
+			Connections: make([]*connectionInfo, 0),
+		}
+		ws.mu.RLock()
+		for h := range ws.handlers {
+			if uid != 0 && h.uid != uid {
+				continue
+			}
+			resp.Connections = append(resp.Connections, ws.infoLocked(h))
+		}
+		ws.mu.RUnlock()
+		slices.SortFunc(resp.Connections, func(a, b *connectionInfo) int {
+			return a.ConnectedAt.Compare(b.ConnectedAt)
+		})
+		responses.SendJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (ws *WS) infoLocked(h *connectionHandler) *connectionInfo {
+	transport := "websocket"
+	if h.conn == nil {
+		transport = "sse"
+		if _, ok := ws.polls[h.id]; ok {
+			transport = "poll"
+		}
+	}
+	roomIDs := make([]int64, 0, len(h.rooms))
+	for roomID := range h.rooms {
+		roomIDs = append(roomIDs, roomID)
+	}
+	slices.Sort(roomIDs)
+	return &connectionInfo{
+		ID:          h.id,
+		UID:         h.uid,
+		Transport:   transport,
+		RoomIDs:     roomIDs,
+		RemoteAddr:  h.remoteAddr,
+		ConnectedAt: h.connectedAt,
+	}
+}
+
+// DisconnectUser closes every connection of the user on every gateway
+// instance: this one right away, the others through the events topic.
+func (ws *WS) DisconnectUser() http.HandlerFunc {
+	const op = "websocket.DisconnectUser"
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid, err := strconv.ParseInt(chi.URLParam(r, URLParamUID), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid UID", http.StatusBadRequest)
+			return
+		}
+		closed := ws.disconnectUser(uid)
+		if err := ws.services.Producer.Send(r.Context(), models.NewDisconnectUserEvent(uid)); err != nil {
+			ws.services.Log.Error(
+				op,
+				"error", err,
+				"uid", uid,
+			)
+			http.Error(w, "failed to reach other instances", http.StatusBadGateway)
+			return
+		}
+		responses.SendJSON(w, http.StatusAccepted, map[string]int{"Closed": closed})
+	}
+}
+
+func (ws *WS) disconnectUser(uid int64) int {
+	ws.mu.RLock()
+	conns := make([]*connectionHandler, 0, len(ws.byUser[uid]))
+	for h := range ws.byUser[uid] {
+		conns = append(conns, h)
+	}
+	ws.mu.RUnlock()
+	for _, h := range conns {
+		h.disconnect(CloseDisconnected, "disconnected by administrator")
+	}
+	return len(conns)
+}
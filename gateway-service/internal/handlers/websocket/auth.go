@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/middleware"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+	sessionpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/session"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const bearerPrefix = "bearer."
+
+type credentials struct {
+	token     string
+	uid       int64
+	expiresAt time.Time
+}
+
+// bearerToken returns the token offered as a "bearer.<jwt>" subprotocol.
+// The server never selects it, so clients offer it next to json or
+// protobuf.
+func bearerToken(r *http.Request) string {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if token, ok := strings.CutPrefix(protocol, bearerPrefix); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+func (ws *WS) verify(ctx context.Context, token string) (*credentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, ws.services.Timeouts.Session)
+	defer cancel()
+	resp, err := ws.services.Session.Verify(ctx, &sessionpb.VerifyRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	c := &credentials{token: token, uid: resp.UID}
+	if resp.ExpiresAt != nil {
+		c.expiresAt = resp.ExpiresAt.AsTime()
+	}
+	return c, nil
+}
+
+type ticketResponse struct {
+	Ticket string `json:"Ticket"`
+}
+
+// Ticket trades the token of an authenticated request for a single-use
+// ticket to pass to /events or /poll instead of the token itself.
+func (ws *WS) Ticket() http.HandlerFunc {
+	const op = "websocket.Ticket"
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(middleware.UIDContextKey).(int64)
+		t, err := ws.services.Tickets.Issue(r.Context(), uid)
+		if err != nil {
+			ws.services.Log.Error(
+				op,
+				"error", err,
+				"uid", uid,
+			)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		responses.SendJSON(w, http.StatusOK, ticketResponse{Ticket: t})
+	}
+}
+
+// tokenClose tells whether a failed verification means the token itself is
+// no longer good, and which close code reports it.
+func tokenClose(err error) (int, string, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unauthenticated {
+		return 0, "", false
+	}
+	if st.Message() == "token expired" {
+		return CloseTokenExpired, "token expired", true
+	}
+	return CloseTokenRevoked, "token revoked", true
+}
+
+// authFrame authenticates a connection that didn't offer a bearer
+// subprotocol: its first frame must be {"Type":"auth","Token":"..."}.
+func (h *connectionHandler) authFrame() *credentials {
+	const op = "websocket.authFrame"
+	h.conn.SetReadDeadline(time.Now().Add(h.ws.cfg.AuthTimeout))
+	code, text := CloseUnauthenticated, "authentication required"
+	r, err := h.readRequest()
+	if err == nil && r.Type == "auth" {
+		c, err := h.ws.verify(context.Background(), r.Token)
+		if err == nil {
+			h.conn.SetReadDeadline(time.Now().Add(h.ws.cfg.PongWait))
+			h.requestID = r.RequestID
+			h.reply(models.NewAuthResponse(c.expiresAt))
+			h.requestID = ""
+			return c
+		}
+		if closeCode, closeText, ok := tokenClose(err); !ok {
+			h.ws.services.Log.Error(
+				op,
+				"error", err,
+			)
+		} else if closeCode == CloseTokenExpired {
+			code, text = closeCode, closeText
+		} else {
+			text = "invalid token"
+		}
+	}
+	h.reject(code, text)
+	return nil
+}
+
+// reject closes a websocket that never got to run its writer.
+func (h *connectionHandler) reject(code int, text string) {
+	framesPending.Add(-int64(len(h.send)))
+	h.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, text),
+		time.Now().Add(h.ws.cfg.WriteWait),
+	)
+	h.conn.Close()
+	h.cancel()
+}
+
+// refreshToken swaps the connection's token for a newer one of the same
+// user without reconnecting.
+func (h *connectionHandler) refreshToken(token string) {
+	const op = "websocket.reader.refreshToken"
+	c, err := h.ws.verify(context.Background(), token)
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	if c.uid != h.uid {
+		h.replyErr(models.CodeForbidden, "token belongs to another user")
+		return
+	}
+	select {
+	case <-h.refreshed:
+	default:
+	}
+	h.refreshed <- c
+	h.reply(models.NewAuthResponse(c.expiresAt))
+}
+
+// watchToken warns the client token_expiry_notice before its token expires,
+// closes the connection when it does and re-verifies the token every
+// token_recheck, so a token the session service stops accepting closes the
+// connection too.
+func (h *connectionHandler) watchToken(c *credentials) {
+	const op = "websocket.watchToken"
+	cfg := h.ws.cfg
+	recheck := time.NewTicker(cfg.TokenRecheck)
+	defer recheck.Stop()
+	notice := time.NewTimer(time.Hour)
+	defer notice.Stop()
+	expire := time.NewTimer(time.Hour)
+	defer expire.Stop()
+	arm := func() {
+		if c.expiresAt.IsZero() {
+			notice.Stop()
+			expire.Stop()
+			return
+		}
+		notice.Reset(time.Until(c.expiresAt) - cfg.TokenExpiryNotice)
+		expire.Reset(time.Until(c.expiresAt))
+	}
+	arm()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case c = <-h.refreshed:
+			arm()
+		case <-notice.C:
+			h.write(models.NewAuthExpiringEvent(c.expiresAt))
+		case <-expire.C:
+			h.disconnect(CloseTokenExpired, "token expired")
+			return
+		case <-recheck.C:
+			_, err := h.ws.verify(h.ctx, c.token)
+			if err == nil {
+				continue
+			}
+			if code, text, ok := tokenClose(err); ok {
+				h.disconnect(code, text)
+				return
+			}
+			h.ws.services.Log.Warn(
+				op,
+				"error", err,
+				"uid", h.uid,
+			)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
+)
+
+// subscription is the state of one room on one connection. While the gap
+// after LastSeen is being replayed, live messages and revisions are held in
+// pending, and afterwards every live message that was replayed is dropped.
+// IDs are not compared: rows can commit out of ID order, so a live message
+// with a lower ID than the replayed ones may still be new.
+type subscription struct {
+	mu          sync.Mutex
+	backfilling bool
+	pending     []*models.Message
+	replayed    map[int64]struct{}
+}
+
+func (h *connectionHandler) deliver(sub *subscription, msg *models.Message, v any) {
+	if !msg.Stored() && !msg.Revision() {
+		h.write(v)
+		return
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.backfilling {
+		sub.pending = append(sub.pending, msg)
+		return
+	}
+	if _, ok := sub.replayed[msg.ID]; ok && msg.Stored() {
+		return
+	}
+	h.write(v)
+}
+
+func (h *connectionHandler) backfill(roomID, lastSeen int64, sub *subscription) {
+	const op = "websocket.backfill"
+	gap, truncated, err := h.fetchGap(roomID, lastSeen)
+	if err != nil {
+		h.internalErr(op, err)
+		truncated = true
+	}
+	// The gap waits for the writer instead of overflowing the queue, and
+	// sub.mu is not held meanwhile, so broadcasts to other connections don't
+	// wait for a slow client. Live messages stay in pending until it's done.
+	replayed := make(map[int64]struct{}, len(gap))
+	for _, msg := range gap {
+		if !h.writeWait(msg) {
+			return
+		}
+		replayed[msg.ID] = struct{}{}
+	}
+	resp := models.NewBackfillResponse(roomID, len(gap), truncated)
+	resp.SetRequestID(h.requestID)
+	if !h.writeWait(resp) {
+		return
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.replayed = replayed
+	for _, msg := range sub.pending {
+		if _, ok := sub.replayed[msg.ID]; !ok || msg.Revision() {
+			h.write(msg.Outgoing())
+		}
+	}
+	sub.pending = nil
+	sub.backfilling = false
+}
+
+// fetchGap pages back from the newest message until LastSeen. History is
+// ordered by ID, so the first ID at or below LastSeen ends the gap.
+func (h *connectionHandler) fetchGap(roomID, lastSeen int64) ([]*models.Message, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	var gap []*models.Message
+	var lastID int64
+	for {
+		resp, err := h.ws.services.Message.Get(ctx, &msgpb.GetRequest{RoomID: roomID, LastID: lastID})
+		if err != nil {
+			return nil, false, fmt.Errorf("get room %d history: %w", roomID, err)
+		}
+		if len(resp.Messages) == 0 {
+			break
+		}
+		reached := false
+		for _, m := range resp.Messages {
+			if m.ID <= lastSeen {
+				reached = true
+				break
+			}
+			if len(gap) == h.ws.cfg.MaxBackfill {
+				slices.Reverse(gap)
+				return gap, true, nil
+			}
+			gap = append(gap, messageFromProto(m))
+			lastID = m.ID - 1
+		}
+		if reached || lastID <= lastSeen {
+			break
+		}
+	}
+	slices.Reverse(gap)
+	return gap, false, nil
+}
+
+func messageFromProto(m *msgpb.Message) *models.Message {
+	msg := &models.Message{
+		WSResponse: models.WSResponse{Type: m.Type},
+		ID:         m.ID,
+		RoomID:     m.RoomID,
+		UID:        m.UID,
+		Text:       m.Text,
+		Timestamp:  m.Timestamp.AsTime(),
+		ClientID:   m.ClientID,
+		Reactions:  m.Reactions,
+		ReplyTo:    m.ReplyTo,
+		ThreadRoot: m.ThreadRoot,
+		ReplyCount: m.ReplyCount,
+	}
+	if m.EditedAt != nil {
+		editedAt := m.EditedAt.AsTime()
+		msg.EditedAt = &editedAt
+	}
+	if m.DeletedAt != nil {
+		deletedAt := m.DeletedAt.AsTime()
+		msg.DeletedAt = &deletedAt
+	}
+	if m.LastReplyAt != nil {
+		lastReplyAt := m.LastReplyAt.AsTime()
+		msg.LastReplyAt = &lastReplyAt
+	}
+	return msg
+}
+
+// parseLastSeen reads the last_seen query parameter in the
+// "roomID:messageID,roomID:messageID" form.
+func parseLastSeen(raw string) (map[int64]int64, error) {
+	lastSeen := make(map[int64]int64)
+	if raw == "" {
+		return lastSeen, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		room, msg, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid last_seen pair %q", pair)
+		}
+		roomID, err := strconv.ParseInt(room, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last_seen room id %q", room)
+		}
+		msgID, err := strconv.ParseInt(msg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last_seen message id %q", msg)
+		}
+		lastSeen[roomID] = msgID
+	}
+	return lastSeen, nil
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type historyClient struct {
+	msgpb.MessageServiceClient
+	page []*msgpb.Message
+}
+
+func (c *historyClient) Get(ctx context.Context, in *msgpb.GetRequest, opts ...grpc.CallOption) (*msgpb.GetResponse, error) {
+	if in.LastID != 0 {
+		return &msgpb.GetResponse{}, nil
+	}
+	return &msgpb.GetResponse{Messages: c.page}, nil
+}
+
+func TestBackfillWaitsForSlowClient(t *testing.T) {
+	cfg := config.Default()
+	cfg.Websocket.SendQueueSize = 2
+	cfg.Websocket.OverflowPolicy = config.OverflowDisconnect
+	history := &historyClient{}
+	for id := int64(15); id > 10; id-- {
+		history.page = append(history.page, &msgpb.Message{ID: id, RoomID: 1, Type: "message", Timestamp: timestamppb.Now()})
+	}
+	services := &gateway.Services{
+		Message:  history,
+		Log:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Timeouts: &cfg.Services.Timeouts,
+	}
+	h := newHandler(1, newWS(&cfg.Websocket, services), encodingJSON)
+	defer h.cancel()
+
+	received := make(chan []any)
+	go func() {
+		var frames []any
+		for v := range h.send {
+			time.Sleep(time.Millisecond)
+			frames = append(frames, v)
+			if _, ok := v.(*models.BackfillResponse); ok {
+				received <- frames
+				return
+			}
+		}
+	}()
+	h.backfill(1, 10, &subscription{backfilling: true})
+
+	select {
+	case frames := <-received:
+		if len(frames) != 6 {
+			t.Fatalf("got %d frames, want 5 messages and the backfill frame", len(frames))
+		}
+		for i, v := range frames[:5] {
+			if msg, ok := v.(*models.Message); !ok || msg.ID != int64(11+i) {
+				t.Fatalf("frame %d is %+v", i, v)
+			}
+		}
+		if resp := frames[5].(*models.BackfillResponse); resp.Count != 5 || resp.Truncated {
+			t.Fatalf("unexpected backfill frame %+v", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("backfill frame never arrived")
+	}
+	if h.ctx.Err() != nil {
+		t.Fatal("slow client was disconnected")
+	}
+}
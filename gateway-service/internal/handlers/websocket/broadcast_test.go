@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	benchRoomID      = 1
+	benchRoomMembers = 1000
+)
+
+// newBenchRoom connects members clients to one room and returns a channel
+// that receives a value every time any client reads a whole frame.
+func newBenchRoom(b *testing.B, members int) (*WS, <-chan struct{}) {
+	b.Helper()
+	cfg := config.Default()
+	services := &gateway.Services{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ws := newWS(&cfg.Websocket, services)
+	upgrader := newUpgrader(cfg.Websocket)
+	conns := make(chan *websocket.Conn)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		conns <- conn
+	}))
+	b.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dialer := websocket.Dialer{EnableCompression: true}
+	received := make(chan struct{}, members)
+	for uid := range members {
+		client, _, err := dialer.Dial(url, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Cleanup(func() { client.Close() })
+		go func() {
+			for {
+				_, r, err := client.NextReader()
+				if err != nil {
+					return
+				}
+				io.Copy(io.Discard, r)
+				received <- struct{}{}
+			}
+		}()
+		h := newConn(<-conns, int64(uid), ws)
+		ws.mu.Lock()
+		ws.handlers[h] = struct{}{}
+		h.setRoomMember(benchRoomID, &subscription{})
+		ws.mu.Unlock()
+		b.Cleanup(h.cancel)
+		go h.writer()
+	}
+	return ws, received
+}
+
+func BenchmarkBroadcastLargeRoom(b *testing.B) {
+	msg := &models.Message{
+		WSResponse: models.WSResponse{Type: "message"},
+		ID:         42,
+		RoomID:     benchRoomID,
+		UID:        1,
+		Text:       strings.Repeat("the quick brown fox jumps over the lazy dog ", 10),
+		Timestamp:  time.Now(),
+	}
+	b.Run("per-recipient", func(b *testing.B) {
+		ws, received := newBenchRoom(b, benchRoomMembers)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			ws.mu.RLock()
+			for h := range ws.handlersInRoom[benchRoomID] {
+				h.write(msg)
+			}
+			ws.mu.RUnlock()
+			for range benchRoomMembers {
+				<-received
+			}
+		}
+	})
+	b.Run("prepared", func(b *testing.B) {
+		ws, received := newBenchRoom(b, benchRoomMembers)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			broadcast(ws, msg)
+			for range benchRoomMembers {
+				<-received
+			}
+		}
+	})
+}
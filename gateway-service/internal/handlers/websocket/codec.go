@@ -0,0 +1,120 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	wspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/wsframe"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	SubprotocolJSON     = "json"
+	SubprotocolProtobuf = "protobuf"
+)
+
+type encoding int
+
+const (
+	encodingJSON encoding = iota
+	encodingProtobuf
+	encodings
+)
+
+var errUnexpectedFrame = errors.New("unexpected websocket frame type")
+
+func encodingOf(conn *websocket.Conn) encoding {
+	if conn.Subprotocol() == SubprotocolProtobuf {
+		return encodingProtobuf
+	}
+	return encodingJSON
+}
+
+func encode(enc encoding, v any) (int, []byte, error) {
+	if enc == encodingJSON {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return 0, nil, err
+		}
+		return websocket.TextMessage, data, nil
+	}
+	frame, err := toFrame(v)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := proto.Marshal(frame)
+	if err != nil {
+		return 0, nil, err
+	}
+	return websocket.BinaryMessage, data, nil
+}
+
+// frame is one encoded broadcast: websocket connections write the prepared
+// message, streams without a websocket write data as is.
+type frame struct {
+	data     []byte
+	prepared *websocket.PreparedMessage
+}
+
+func prepare(enc encoding, v any) (*frame, error) {
+	msgType, data, err := encode(enc, v)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := websocket.NewPreparedMessage(msgType, data)
+	if err != nil {
+		return nil, err
+	}
+	return &frame{data: data, prepared: pm}, nil
+}
+
+// frames encodes one broadcast lazily, at most once per encoding.
+type frames struct {
+	v   any
+	enc [encodings]*frame
+	err error
+}
+
+func (f *frames) get(enc encoding) (*frame, error) {
+	if f.enc[enc] == nil && f.err == nil {
+		f.enc[enc], f.err = prepare(enc, f.v)
+	}
+	return f.enc[enc], f.err
+}
+
+func (h *connectionHandler) readRequest() (*models.WSRequest, error) {
+	r := new(models.WSRequest)
+	if h.encoding == encodingJSON {
+		return r, h.conn.ReadJSON(r)
+	}
+	msgType, data, err := h.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if msgType != websocket.BinaryMessage {
+		return nil, errUnexpectedFrame
+	}
+	req := &wspb.Request{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	r.Type = req.Type
+	r.RequestID = req.RequestID
+	r.Text = req.Text
+	r.RoomID = req.RoomID
+	r.RoomIDs = req.RoomIDs
+	r.LastSeen = req.LastSeen
+	r.Status = req.Status
+	r.MessageID = req.MessageID
+	r.ClientID = req.ClientID
+	r.Token = req.Token
+	r.Name = req.Name
+	r.IsPrivate = req.IsPrivate
+	r.UID = req.UID
+	r.LastID = req.LastID
+	r.Emoji = req.Emoji
+	r.ReplyTo = req.ReplyTo
+	return r, nil
+}
@@ -0,0 +1,46 @@
+package websocket
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+func (ws *WS) isDraining() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.draining
+}
+
+// beginSend registers an in-flight Message.Send, so that Shutdown waits for
+// it. Once the gateway is draining no new sends are started.
+func (ws *WS) beginSend() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	if ws.draining {
+		return false
+	}
+	ws.sends.Add(1)
+	return true
+}
+
+// goAway tells the client to reconnect after a random delay of up to
+// reconnect_jitter, so that clients of a stopping instance don't all come
+// back at once, and closes the connection with 1001.
+func (h *connectionHandler) goAway() {
+	reconnectIn := time.Duration(rand.Int64N(int64(h.ws.cfg.ReconnectJitter) + 1))
+	h.closeOnce.Do(func() {
+		h.closeMsg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown")
+		h.farewell = models.NewGoingAwayEvent(reconnectIn)
+	})
+	h.cancel()
+}
+
+// goingAway returns the server_going_away frame to write before closing, or
+// nil if the connection is not closed by a drain.
+func (h *connectionHandler) goingAway() *models.GoingAwayEvent {
+	h.closeMessage()
+	return h.farewell
+}
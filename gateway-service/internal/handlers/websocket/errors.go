@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var grpcToWS = map[codes.Code]string{
+	codes.InvalidArgument:    models.CodeInvalidRequest,
+	codes.OutOfRange:         models.CodeInvalidRequest,
+	codes.NotFound:           models.CodeNotFound,
+	codes.AlreadyExists:      models.CodeAlreadyExists,
+	codes.PermissionDenied:   models.CodeForbidden,
+	codes.FailedPrecondition: models.CodeFailedPrecondition,
+	codes.Unauthenticated:    models.CodeUnauthenticated,
+	codes.ResourceExhausted:  models.CodeRateLimited,
+	codes.Unavailable:        models.CodeUnavailable,
+	codes.DeadlineExceeded:   models.CodeTimeout,
+}
+
+type response interface {
+	SetRequestID(id string)
+}
+
+// reply answers the request being routed, echoing its RequestID.
+func (h *connectionHandler) reply(v response) {
+	h.replyTo(h.requestID, v)
+}
+
+// replyTo answers a request that is no longer being routed, e.g. from a
+// background retry.
+func (h *connectionHandler) replyTo(requestID string, v response) {
+	v.SetRequestID(requestID)
+	h.write(v)
+}
+
+func (h *connectionHandler) replyErr(code, msg string) {
+	h.reply(models.NewWSError(code, msg))
+}
+
+func (h *connectionHandler) internalErr(op string, err error) {
+	h.replyErr(models.CodeInternal, "internal error")
+	h.ws.services.Log.Error(
+		op,
+		"error", err,
+		"uid", h.uid,
+	)
+}
+
+func (h *connectionHandler) grpcErr(op string, err error) {
+	if st, ok := status.FromError(err); ok {
+		if code, ok := grpcToWS[st.Code()]; ok {
+			h.replyErr(code, st.Message())
+			return
+		}
+	}
+	h.internalErr(op, err)
+}
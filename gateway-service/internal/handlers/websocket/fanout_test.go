@@ -0,0 +1,238 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
+	gwkafka "github.com/P3rCh1/chat-server/gateway-service/internal/kafka"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
+	sessionpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/session"
+	"github.com/gorilla/websocket"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+)
+
+// standInBroker keeps one shared log and one cursor per consumer group, so
+// readers of the same group split the events, while different groups and
+// readers without a group each see all of them, like a Kafka topic does.
+type standInBroker struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	log    []kafka.Message
+	groups map[string]*int
+}
+
+func newStandInBroker() *standInBroker {
+	b := &standInBroker{groups: make(map[string]*int)}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *standInBroker) Publish(t *testing.T, msg *models.Message) {
+	t.Helper()
+	value, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.WriteMessages(context.Background(), kafka.Message{
+		Key:   []byte(strconv.FormatInt(msg.RoomID, 10)),
+		Value: value,
+	})
+}
+
+func (b *standInBroker) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	b.mu.Lock()
+	b.log = append(b.log, msgs...)
+	b.mu.Unlock()
+	b.cond.Broadcast()
+	return nil
+}
+
+func (b *standInBroker) Close() error {
+	return nil
+}
+
+func (b *standInBroker) NewReader(cfg kafka.ReaderConfig) gwkafka.Reader {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if cfg.GroupID == "" {
+		offset := len(b.log)
+		return &standInReader{broker: b, offset: &offset}
+	}
+	offset, ok := b.groups[cfg.GroupID]
+	if !ok {
+		offset = new(int)
+		b.groups[cfg.GroupID] = offset
+	}
+	return &standInReader{broker: b, offset: offset}
+}
+
+type standInReader struct {
+	broker *standInBroker
+	offset *int
+	closed bool
+}
+
+func (r *standInReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	b := r.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !r.closed && *r.offset >= len(b.log) {
+		b.cond.Wait()
+	}
+	if r.closed {
+		return kafka.Message{}, io.EOF
+	}
+	msg := b.log[*r.offset]
+	*r.offset++
+	return msg, nil
+}
+
+func (r *standInReader) Close() error {
+	r.broker.mu.Lock()
+	r.closed = true
+	r.broker.mu.Unlock()
+	r.broker.cond.Broadcast()
+	return nil
+}
+
+type stubSession struct {
+	sessionpb.SessionClient
+}
+
+func (stubSession) Verify(
+	ctx context.Context,
+	r *sessionpb.VerifyRequest,
+	opts ...grpc.CallOption,
+) (*sessionpb.VerifyResponse, error) {
+	uid, err := strconv.ParseInt(r.Token, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionpb.VerifyResponse{UID: uid}, nil
+}
+
+type stubRooms struct {
+	roomspb.RoomsClient
+}
+
+func (stubRooms) IsMember(
+	ctx context.Context,
+	r *roomspb.IsMemberRequest,
+	opts ...grpc.CallOption,
+) (*roomspb.IsMemberResponse, error) {
+	return &roomspb.IsMemberResponse{IsMember: true}, nil
+}
+
+// startGateway runs one gateway instance against the stand-in brokers for
+// the messages and the events topics.
+func startGateway(t *testing.T, messages, events *standInBroker, instanceID string) *httptest.Server {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Kafka.InstanceID = instanceID
+	cfg.Kafka.WorkerCount = 2
+	services := &gateway.Services{
+		Session:    stubSession{},
+		Rooms:      stubRooms{},
+		Kafka:      gwkafka.NewConsumerFromReader(messages.NewReader(gwkafka.ReaderConfig(cfg.Kafka)), cfg.Kafka.Timeout),
+		Events:     gwkafka.NewConsumerFromReader(events.NewReader(gwkafka.EventsReaderConfig(cfg.Kafka)), cfg.Kafka.Timeout),
+		UserEvents: gwkafka.NewConsumerFromReader(newStandInBroker().NewReader(gwkafka.UserEventsReaderConfig(cfg.Kafka)), cfg.Kafka.Timeout),
+		Producer:   gwkafka.NewProducerFromWriter(events),
+		Log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Timeouts:   &cfg.Services.Timeouts,
+	}
+	ws := New(cfg, services)
+	server := httptest.NewServer(ws.Connector())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := ws.Shutdown(ctx); err != nil {
+			t.Error(err)
+		}
+		services.Kafka.Close()
+		services.Events.Close()
+		services.UserEvents.Close()
+		server.Close()
+	})
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, uid int64) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dialer := websocket.Dialer{Subprotocols: []string{SubprotocolJSON, bearerPrefix + strconv.FormatInt(uid, 10)}}
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	frame := make(map[string]any)
+	if err := conn.ReadJSON(&frame); err != nil {
+		t.Fatal(err)
+	}
+	return frame
+}
+
+func enter(t *testing.T, conn *websocket.Conn, roomID int64) {
+	t.Helper()
+	if err := conn.WriteJSON(models.WSRequest{Type: "enter", RoomID: roomID}); err != nil {
+		t.Fatal(err)
+	}
+	if frame := readFrame(t, conn); frame["Type"] != "enter" {
+		t.Fatalf("expected enter response, got %v", frame)
+	}
+}
+
+func TestBroadcastFanOutReachesEveryInstance(t *testing.T) {
+	const (
+		roomID   = 7
+		messages = 20
+	)
+	broker := newStandInBroker()
+	events := newStandInBroker()
+	first := startGateway(t, broker, events, "gateway-1")
+	second := startGateway(t, broker, events, "gateway-2")
+	clients := []*websocket.Conn{dial(t, first, 1), dial(t, second, 2)}
+	for _, conn := range clients {
+		enter(t, conn, roomID)
+	}
+	for i := range messages {
+		broker.Publish(t, &models.Message{
+			WSResponse: models.WSResponse{Type: "message"},
+			ID:         int64(i + 1),
+			RoomID:     roomID,
+			UID:        1,
+			Text:       "hello",
+		})
+	}
+	for n, conn := range clients {
+		received := make(map[int64]bool)
+		for range messages {
+			frame := readFrame(t, conn)
+			if frame["Type"] != "message" {
+				t.Fatalf("client %d: unexpected frame %v", n, frame)
+			}
+			received[int64(frame["ID"].(float64))] = true
+		}
+		if len(received) != messages {
+			t.Fatalf("client %d: got %d distinct messages, want %d", n, len(received), messages)
+		}
+	}
+}
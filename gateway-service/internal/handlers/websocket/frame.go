@@ -0,0 +1,277 @@
+package websocket
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	wspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/wsframe"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toFrame maps an outgoing value to the frame protobuf clients receive. A
+// type without a mapping is an error rather than an empty frame.
+func toFrame(v any) (*wspb.Frame, error) {
+	switch v := v.(type) {
+	case *models.Message:
+		return messageFrame(v), nil
+	case *models.ReadReceipt:
+		return &wspb.Frame{
+			Type:       v.Type,
+			RequestID:  v.RequestID,
+			RoomID:     v.RoomID,
+			UID:        v.UID,
+			LastReadID: v.LastReadID,
+			Timestamp:  timestamppb.New(v.Timestamp),
+		}, nil
+	case *models.MessageRevision:
+		return &wspb.Frame{
+			Type:          v.Type,
+			RequestID:     v.RequestID,
+			ID:            v.ID,
+			RoomID:        v.RoomID,
+			UID:           v.UID,
+			Text:          v.Text,
+			Timestamp:     timestamppb.New(v.Timestamp),
+			EditedAt:      optionalTime(v.EditedAt),
+			DeletedAt:     optionalTime(v.DeletedAt),
+			DeletedBy:     v.DeletedBy,
+			Emoji:         v.Emoji,
+			ReactionCount: v.ReactionCount,
+		}, nil
+	case *models.Event:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			RoomIDs:   v.RoomIDs,
+			UID:       v.UID,
+			Status:    v.Status,
+			LastSeen:  optionalTime(v.LastSeen),
+		}, nil
+	case *models.UserEvent:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			UID:       v.UID,
+			RoomID:    v.RoomID,
+			ByUID:     v.ByUID,
+			Name:      v.Name,
+			Timestamp: timestamppb.New(v.Timestamp),
+		}, nil
+	case *models.WSError:
+		return errorFrame(v), nil
+	case *models.RateLimitedError:
+		f := errorFrame(&v.WSError)
+		f.RetryAfter = v.RetryAfter
+		return f, nil
+	case *models.FailedResponse:
+		f := errorFrame(&v.WSError)
+		f.RoomID = v.RoomID
+		f.ClientID = v.ClientID
+		return f, nil
+	case *models.SentResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			MessageID: v.MessageID,
+			Timestamp: timestamppb.New(v.Timestamp),
+			ClientID:  v.ClientID,
+		}, nil
+	case *models.EditResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			MessageID: v.MessageID,
+			RoomID:    v.RoomID,
+			EditedAt:  timestamppb.New(v.EditedAt),
+		}, nil
+	case *models.DeleteResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			MessageID: v.MessageID,
+			RoomID:    v.RoomID,
+			DeletedAt: timestamppb.New(v.DeletedAt),
+		}, nil
+	case *models.ReactionResponse:
+		return &wspb.Frame{
+			Type:          v.Type,
+			RequestID:     v.RequestID,
+			MessageID:     v.MessageID,
+			RoomID:        v.RoomID,
+			Emoji:         v.Emoji,
+			ReactionCount: &v.ReactionCount,
+		}, nil
+	case *models.ReadResponse:
+		return &wspb.Frame{
+			Type:       v.Type,
+			RequestID:  v.RequestID,
+			RoomID:     v.RoomID,
+			MessageID:  v.MessageID,
+			LastReadID: v.LastReadID,
+		}, nil
+	case *models.StatusResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			Status:    v.Status,
+		}, nil
+	case *models.QueuedResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			ClientID:  v.ClientID,
+		}, nil
+	case *models.BackfillResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			Count:     int32(v.Count),
+			Truncated: v.Truncated,
+		}, nil
+	case *models.AuthResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			ExpiresAt: timestamppb.New(v.ExpiresAt),
+		}, nil
+	case *models.GoingAwayEvent:
+		return &wspb.Frame{
+			Type:        v.Type,
+			RequestID:   v.RequestID,
+			ReconnectIn: v.ReconnectIn,
+		}, nil
+	case *models.RoomsResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomIDs:   v.RoomIDs,
+		}, nil
+	case *models.RoomIDResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			UID:       v.UID,
+		}, nil
+	case *models.RoomResponse:
+		return &wspb.Frame{
+			Type:       v.Type,
+			RequestID:  v.RequestID,
+			RoomID:     v.RoomID,
+			Name:       v.Name,
+			CreatorUID: v.CreatorUID,
+			IsPrivate:  v.IsPrivate,
+			IsDirect:   v.IsDirect,
+			CreatedAt:  timestamppb.New(v.CreatedAt),
+		}, nil
+	case *models.DirectResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			UID:       v.UID,
+			Created:   v.Created,
+		}, nil
+	case *models.UserRoomsResponse:
+		directs := make([]*wspb.Frame, len(v.Directs))
+		for i, d := range v.Directs {
+			directs[i] = &wspb.Frame{
+				RoomID:    d.RoomID,
+				UID:       d.UID,
+				Username:  d.Username,
+				Email:     d.Email,
+				CreatedAt: optionalTime(d.CreatedAt),
+			}
+		}
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomIDs:   v.RoomIDs,
+			Directs:   directs,
+			Unread:    v.Unread,
+		}, nil
+	case *models.HistoryResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			Messages:  messageFrames(v.Messages),
+		}, nil
+	case *models.ThreadResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			RoomID:    v.RoomID,
+			MessageID: v.MessageID,
+			Messages:  messageFrames(v.Messages),
+		}, nil
+	case *models.ProfileResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			UID:       v.UID,
+			Username:  v.Username,
+			Email:     v.Email,
+			CreatedAt: timestamppb.New(v.CreatedAt),
+		}, nil
+	case *models.NameResponse:
+		return &wspb.Frame{
+			Type:      v.Type,
+			RequestID: v.RequestID,
+			Name:      v.Name,
+		}, nil
+	}
+	return nil, fmt.Errorf("no protobuf frame for %T", v)
+}
+
+func messageFrame(m *models.Message) *wspb.Frame {
+	return &wspb.Frame{
+		Type:          m.Type,
+		RequestID:     m.RequestID,
+		ID:            m.ID,
+		RoomID:        m.RoomID,
+		UID:           m.UID,
+		Text:          m.Text,
+		Timestamp:     timestamppb.New(m.Timestamp),
+		LastReadID:    m.LastReadID,
+		ClientID:      m.ClientID,
+		EditedAt:      optionalTime(m.EditedAt),
+		DeletedAt:     optionalTime(m.DeletedAt),
+		DeletedBy:     m.DeletedBy,
+		Emoji:         m.Emoji,
+		ReactionCount: m.ReactionCount,
+		Reactions:     m.Reactions,
+		ReplyTo:       m.ReplyTo,
+		ThreadRoot:    m.ThreadRoot,
+		ReplyCount:    m.ReplyCount,
+		LastReplyAt:   optionalTime(m.LastReplyAt),
+	}
+}
+
+func messageFrames(messages []*models.Message) []*wspb.Frame {
+	out := make([]*wspb.Frame, len(messages))
+	for i, m := range messages {
+		out[i] = messageFrame(m)
+	}
+	return out
+}
+
+func errorFrame(e *models.WSError) *wspb.Frame {
+	return &wspb.Frame{
+		Type:      e.Type,
+		RequestID: e.RequestID,
+		Code:      e.Code,
+		Error:     e.Error,
+	}
+}
+
+func optionalTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
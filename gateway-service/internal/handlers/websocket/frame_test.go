@@ -0,0 +1,190 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	wspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/wsframe"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// frameSamples holds one value of every outgoing type with all fields set,
+// so a field that toFrame or wsframe.proto misses shows up in the test.
+func frameSamples() []any {
+	ts := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	count := int64(3)
+	resp := models.WSResponse{Type: "t", RequestID: "r1"}
+	wsErr := models.WSError{WSResponse: resp, Code: models.CodeInternal, Error: "e"}
+	msg := &models.Message{
+		WSResponse:    resp,
+		ID:            42,
+		RoomID:        1,
+		UID:           5,
+		Text:          "text",
+		Timestamp:     ts,
+		LastReadID:    40,
+		ClientID:      "c1",
+		EditedAt:      &ts,
+		DeletedAt:     &ts,
+		DeletedBy:     1,
+		Emoji:         "👍",
+		ReactionCount: &count,
+		Reactions:     map[string]int64{"👍": 3},
+		ReplyTo:       41,
+		ThreadRoot:    40,
+		ReplyCount:    2,
+		LastReplyAt:   &ts,
+	}
+	return []any{
+		msg,
+		&models.ReadReceipt{WSResponse: resp, RoomID: 1, UID: 5, LastReadID: 40, Timestamp: ts},
+		&models.MessageRevision{
+			WSResponse:    resp,
+			ID:            42,
+			RoomID:        1,
+			UID:           5,
+			Text:          "text",
+			Timestamp:     ts,
+			EditedAt:      &ts,
+			DeletedAt:     &ts,
+			DeletedBy:     1,
+			Emoji:         "👍",
+			ReactionCount: &count,
+		},
+		&models.Event{WSResponse: resp, RoomID: 1, RoomIDs: []int64{1, 2}, UID: 5, Status: "away", LastSeen: &ts},
+		&models.UserEvent{WSResponse: resp, UID: 5, RoomID: 1, ByUID: 2, Name: "n", Timestamp: ts},
+		&wsErr,
+		&models.RateLimitedError{WSError: wsErr, RetryAfter: 250},
+		&models.FailedResponse{WSError: wsErr, RoomID: 1, ClientID: "c1"},
+		&models.SentResponse{WSResponse: resp, MessageID: 42, Timestamp: ts, ClientID: "c1"},
+		&models.EditResponse{WSResponse: resp, MessageID: 42, RoomID: 1, EditedAt: ts},
+		&models.DeleteResponse{WSResponse: resp, MessageID: 42, RoomID: 1, DeletedAt: ts},
+		&models.ReactionResponse{WSResponse: resp, MessageID: 42, RoomID: 1, Emoji: "👍", ReactionCount: 3},
+		&models.ReadResponse{WSResponse: resp, RoomID: 1, MessageID: 42, LastReadID: 42},
+		&models.StatusResponse{WSResponse: resp, Status: "away"},
+		&models.QueuedResponse{WSResponse: resp, RoomID: 1, ClientID: "c1"},
+		&models.BackfillResponse{WSResponse: resp, RoomID: 1, Count: 3, Truncated: true},
+		&models.AuthResponse{WSResponse: resp, ExpiresAt: ts},
+		&models.GoingAwayEvent{WSResponse: resp, ReconnectIn: 1000},
+		&models.RoomsResponse{WSResponse: resp, RoomIDs: []int64{1, 2}},
+		&models.RoomIDResponse{WSResponse: resp, RoomID: 1, UID: 5},
+		&models.RoomResponse{
+			WSResponse: resp,
+			RoomID:     1,
+			Name:       "room",
+			CreatorUID: 5,
+			IsPrivate:  true,
+			IsDirect:   true,
+			CreatedAt:  ts,
+		},
+		&models.DirectResponse{WSResponse: resp, RoomID: 9, UID: 7, Created: true},
+		&models.UserRoomsResponse{
+			WSResponse: resp,
+			RoomIDs:    []int64{1, 2},
+			Directs:    []*models.DirectRoom{{RoomID: 9, UID: 7, Username: "u", Email: "e", CreatedAt: &ts}},
+			Unread:     map[int64]int64{1: 3},
+		},
+		&models.HistoryResponse{WSResponse: resp, RoomID: 1, Messages: []*models.Message{msg}},
+		&models.ThreadResponse{WSResponse: resp, RoomID: 1, MessageID: 40, Messages: []*models.Message{msg}},
+		&models.ProfileResponse{WSResponse: resp, UID: 5, Username: "u", Email: "e", CreatedAt: ts},
+		&models.NameResponse{WSResponse: resp, Name: "n"},
+	}
+}
+
+// checkFilled fails on a zero field, looking into embedded structs.
+func checkFilled(t *testing.T, v reflect.Value, typ string) {
+	t.Helper()
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			checkFilled(t, v.Field(i), typ)
+			continue
+		}
+		if v.Field(i).IsZero() {
+			t.Errorf("%s sample leaves %s empty", typ, field.Name)
+		}
+	}
+}
+
+// TestFrameMatchesJSON checks that every outgoing type maps to the same
+// frame as its JSON form read strictly into wspb.Frame, so neither a key
+// missing from wsframe.proto nor a field missing from toFrame goes unnoticed.
+func TestFrameMatchesJSON(t *testing.T) {
+	for _, v := range frameSamples() {
+		typ := reflect.TypeOf(v).Elem().Name()
+		checkFilled(t, reflect.ValueOf(v), typ)
+		got, err := toFrame(v)
+		if err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+		want := &wspb.Frame{}
+		if err := protojson.Unmarshal(data, want); err != nil {
+			t.Errorf("%s: JSON doesn't fit wsframe.proto: %v", typ, err)
+			continue
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("%s: frame %v, JSON gives %v", typ, got, want)
+		}
+	}
+}
+
+func TestFrameRejectsUnknownType(t *testing.T) {
+	if _, _, err := encode(encodingProtobuf, &models.Presence{}); err == nil {
+		t.Fatal("expected an error for a type without a frame")
+	}
+}
+
+func TestOutgoingKeys(t *testing.T) {
+	count := int64(1)
+	for _, tc := range []struct {
+		msg     *models.Message
+		present []string
+		absent  []string
+	}{
+		{
+			&models.Message{WSResponse: models.WSResponse{Type: "message"}, ID: 42, RoomID: 1, UID: 5},
+			[]string{"ID", "Text"},
+			nil,
+		},
+		{
+			&models.Message{WSResponse: models.WSResponse{Type: "read"}, RoomID: 1, UID: 5, LastReadID: 40},
+			[]string{"LastReadID"},
+			[]string{"ID", "Text"},
+		},
+		{
+			&models.Message{WSResponse: models.WSResponse{Type: "reaction_added"}, ID: 42, RoomID: 1, UID: 5, Emoji: "👍", ReactionCount: &count},
+			[]string{"ID", "Emoji", "ReactionCount"},
+			[]string{"Text", "ClientID", "Reactions"},
+		},
+	} {
+		data, err := json.Marshal(tc.msg.Outgoing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := make(map[string]any)
+		if err := json.Unmarshal(data, &keys); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range tc.present {
+			if _, ok := keys[key]; !ok {
+				t.Errorf("%s frame %s has no %s", tc.msg.Type, data, key)
+			}
+		}
+		for _, key := range tc.absent {
+			if _, ok := keys[key]; ok {
+				t.Errorf("%s frame %s has %s", tc.msg.Type, data, key)
+			}
+		}
+	}
+}
@@ -5,30 +5,42 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
-	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
-	sessionpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/session"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/ticket"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-)
-
-var (
-	handlersInRoom = make(map[int64]map[*connectionHandler]struct{})
-	mu             sync.RWMutex
 )
 
 type connectionHandler struct {
-	uid        int64
-	roomID     int64
-	conn       *websocket.Conn
-	ws         *WS
-	writeMutex sync.Mutex
-	ctx        context.Context
-	cancel     context.CancelFunc
-	closeDone  chan struct{}
+	id             string
+	uid            int64
+	remoteAddr     string
+	connectedAt    time.Time
+	encoding       encoding
+	requestID      string
+	rooms          map[int64]*subscription
+	conn           *websocket.Conn
+	ws             *WS
+	send           chan any
+	typingMu       sync.Mutex
+	typingIn       map[int64]*typingState
+	presenceMu     sync.Mutex
+	presenceDone   bool
+	refreshed      chan *credentials
+	violations     int
+	violationsFrom time.Time
+	retrying       atomic.Int32
+	closeOnce      sync.Once
+	closeMsg       []byte
+	farewell       *models.GoingAwayEvent
+	ctx            context.Context
+	cancel         context.CancelFunc
+	closeDone      chan struct{}
 }
 
 func (h *connectionHandler) setOptions(cfg *config.Websocket) {
@@ -41,72 +53,198 @@ func (h *connectionHandler) setOptions(cfg *config.Websocket) {
 }
 
 func newConn(conn *websocket.Conn, uid int64, ws *WS) *connectionHandler {
+	h := newHandler(uid, ws, encodingOf(conn))
+	h.conn = conn
+	h.remoteAddr = conn.RemoteAddr().String()
+	return h
+}
+
+// newHandler creates a connection without a websocket, for transports that
+// only stream frames to the client.
+func newHandler(uid int64, ws *WS, enc encoding) *connectionHandler {
 	h := &connectionHandler{
-		uid:       uid,
-		roomID:    -1,
-		conn:      conn,
-		ws:        ws,
-		closeDone: make(chan struct{}),
+		id:          uuid.New().String(),
+		uid:         uid,
+		connectedAt: time.Now().UTC(),
+		encoding:    enc,
+		rooms:       make(map[int64]*subscription),
+		typingIn:    make(map[int64]*typingState),
+		ws:          ws,
+		send:        make(chan any, ws.cfg.SendQueueSize),
+		refreshed:   make(chan *credentials, 1),
+		closeDone:   make(chan struct{}),
 	}
 	h.ctx, h.cancel = context.WithCancel(context.Background())
 	return h
 }
 
-func Connector(
-	cfg *config.Config,
-	services *gateway.Services,
-) http.HandlerFunc {
+// authenticate identifies the user of an SSE or long-poll request by the
+// Authorization header, or, since EventSource can't set headers, by a
+// ticket from /events/ticket in the ticket query parameter.
+func (ws *WS) authenticate(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	const op = "websocket.authenticate"
+	if token := r.Header.Get("Authorization"); token != "" {
+		c, err := ws.verify(r.Context(), token)
+		if err != nil {
+			responses.GatewayGRPCErr(w, ws.services.Log, "auth", err)
+			return 0, false
+		}
+		return c.uid, true
+	}
+	t := r.URL.Query().Get("ticket")
+	if t == "" {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return 0, false
+	}
+	uid, err := ws.services.Tickets.Redeem(r.Context(), t)
+	if errors.Is(err, ticket.ErrUnknown) {
+		http.Error(w, "invalid ticket", http.StatusUnauthorized)
+		return 0, false
+	}
+	if err != nil {
+		ws.services.Log.Error(
+			op,
+			"error", err,
+		)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return 0, false
+	}
+	return uid, true
+}
+
+// register adds a connection to the ones Shutdown closes and to its
+// user's ones, unless the user already has max_conns_per_user of them on
+// this instance. A connection that raced with the start of a drain is sent
+// away at once.
+func (ws *WS) register(h *connectionHandler) bool {
+	ws.mu.Lock()
+	ok := ws.addLocked(h)
+	draining := ws.draining
+	ws.mu.Unlock()
+	if ok && draining {
+		h.goAway()
+	}
+	return ok
+}
+
+// userFull answers early for connections that authenticate before the
+// upgrade; register still has the final word.
+func (ws *WS) userFull(uid int64) bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	limit := ws.cfg.MaxConnsPerUser
+	return limit > 0 && len(ws.byUser[uid]) >= limit
+}
+
+func (ws *WS) addLocked(h *connectionHandler) bool {
+	conns := ws.byUser[h.uid]
+	if limit := ws.cfg.MaxConnsPerUser; limit > 0 && len(conns) >= limit {
+		return false
+	}
+	if conns == nil {
+		conns = make(map[*connectionHandler]struct{})
+		ws.byUser[h.uid] = conns
+	}
+	conns[h] = struct{}{}
+	ws.handlers[h] = struct{}{}
+	return true
+}
+
+func (ws *WS) removeLocked(h *connectionHandler) {
+	delete(ws.handlers, h)
+	delete(ws.polls, h.id)
+	if conns := ws.byUser[h.uid]; conns != nil {
+		delete(conns, h)
+		if len(conns) == 0 {
+			delete(ws.byUser, h.uid)
+		}
+	}
+}
+
+func (ws *WS) Connector() http.HandlerFunc {
 	const op = "websocket.Connector"
-	upgrader := newUpgrader(cfg.Websocket)
-	ws := newWS(&cfg.Websocket, services)
-	StartKafkaWorkers(ws, cfg.Kafka.WorkerCount)
+	upgrader := newUpgrader(*ws.cfg)
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.URL.Query().Get("token")
-		uid, err := services.Session.Verify(r.Context(), &sessionpb.VerifyRequest{Token: token})
+		if ws.isDraining() {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		lastSeen, err := parseLastSeen(r.URL.Query().Get("last_seen"))
 		if err != nil {
-			if status, ok := status.FromError(err); ok && status.Code() == codes.Internal {
-				services.Log.Error(op, "error", status.Message())
-			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		var c *credentials
+		if token := bearerToken(r); token != "" {
+			if c, err = ws.verify(r.Context(), token); err != nil {
+				responses.GatewayGRPCErr(w, ws.services.Log, "auth", err)
+				return
+			}
+			if ws.userFull(c.uid) {
+				http.Error(w, "too many connections", http.StatusTooManyRequests)
+				return
+			}
+		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
-			services.Log.Error(op, "error", err)
+			ws.services.Log.Error(op, "error", err)
 			return
 		}
-		h := newConn(conn, uid.UID, ws)
-		h.setOptions(&cfg.Websocket)
-		h.enter(0)
-		go h.reader()
-		go h.pinger()
+		go ws.serve(conn, c, lastSeen)
 	}
 }
 
-func Shutdown(ctx context.Context) error {
-	var grace = true
-	stopWorkers()
-	var wg sync.WaitGroup
-	mu.RLock()
-	for _, handlersInRoom := range handlersInRoom {
-		wg.Add(len(handlersInRoom))
-		for h := range handlersInRoom {
-			go func(h *connectionHandler) {
-				defer wg.Done()
-				h.cancel()
-				select {
-				case <-ctx.Done():
-					grace = false
-					return
-				case <-h.closeDone:
-					return
-				}
-			}(h)
+// serve authenticates a connection that came without a bearer subprotocol
+// before anything else runs for it.
+func (ws *WS) serve(conn *websocket.Conn, c *credentials, lastSeen map[int64]int64) {
+	h := newConn(conn, 0, ws)
+	h.setOptions(ws.cfg)
+	if c == nil {
+		if c = h.authFrame(); c == nil {
+			return
 		}
 	}
-	mu.RUnlock()
-	wg.Wait()
-	if !grace {
-		return errors.New("shutdown timer end before close websocket")
+	h.uid = c.uid
+	if !ws.register(h) {
+		h.reject(CloseTooManyConns, "too many connections")
+		return
+	}
+	go h.writer()
+	go h.watchToken(c)
+	h.reader(lastSeen)
+}
+
+// Shutdown drains the gateway: new connections are refused, every client
+// gets server_going_away and a 1001 close, and Shutdown returns once
+// in-flight sends are done and connections are closed.
+func (ws *WS) Shutdown(ctx context.Context) error {
+	ws.mu.Lock()
+	ws.draining = true
+	handlers := make([]*connectionHandler, 0, len(ws.handlers))
+	for h := range ws.handlers {
+		handlers = append(handlers, h)
+	}
+	ws.mu.Unlock()
+	defer ws.stopWorkers()
+	for _, h := range handlers {
+		h.goAway()
+	}
+	sendsDone := make(chan struct{})
+	go func() {
+		ws.sends.Wait()
+		close(sendsDone)
+	}()
+	select {
+	case <-sendsDone:
+	case <-ctx.Done():
+		return errors.New("shutdown timer end before in-flight sends finished")
+	}
+	for _, h := range handlers {
+		select {
+		case <-h.closeDone:
+		case <-ctx.Done():
+			return errors.New("shutdown timer end before close websocket")
+		}
 	}
 	return nil
 }
@@ -3,52 +3,125 @@ package websocket
 import (
 	"context"
 	"errors"
-	"sync/atomic"
 
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
 	"github.com/segmentio/kafka-go"
 )
 
-var (
-	ctxStopWorkers, stopWorkers = context.WithCancel(context.Background())
-	fatalErrorLoggedFlag        atomic.Bool
-)
+const laneQueueSize = 64
 
+// StartKafkaWorkers broadcasts room messages on n lanes. A room always maps
+// to the same lane, so its messages reach clients in the order the topic
+// holds them, while different rooms are broadcast in parallel.
 func StartKafkaWorkers(
 	ws *WS,
 	n int,
 ) {
-	for range n {
-		go KafkaWorker(ws)
+	lanes := make([]chan *models.Message, n)
+	for i := range lanes {
+		lanes[i] = make(chan *models.Message, laneQueueSize)
+		go LaneWorker(ws, lanes[i])
 	}
+	go KafkaWorker(ws, lanes)
+	go EventsWorker(ws)
+	go UserEventsWorker(ws)
 }
 
-func KafkaWorker(ws *WS) {
+// KafkaWorker is the only reader of the messages topic and hands every
+// message to its room's lane.
+func KafkaWorker(ws *WS, lanes []chan *models.Message) {
+	defer func() {
+		for _, lane := range lanes {
+			close(lane)
+		}
+	}()
 	for {
 		select {
-		case <-ctxStopWorkers.Done():
+		case <-ws.ctxStopWorkers.Done():
 			return
 		default:
 			msg, err := ws.services.Kafka.Read()
 			if err != nil {
-				if errors.Is(err, kafka.ErrGenerationEnded) || errors.Is(err, kafka.ErrGroupClosed) {
-					if fatalErrorLoggedFlag.CompareAndSwap(false, true) {
-						stopWorkers()
-						ws.services.Log.Error(
-							"kafka worker fatal error",
-							"error", err,
-						)
-					}
+				if ws.fatalReadErr(err) {
+					return
+				}
+				continue
+			}
+			select {
+			case lanes[laneOf(msg.RoomID, len(lanes))] <- msg:
+			case <-ws.ctxStopWorkers.Done():
+				return
+			}
+		}
+	}
+}
+
+func LaneWorker(ws *WS, lane <-chan *models.Message) {
+	for msg := range lane {
+		broadcast(ws, msg)
+	}
+}
+
+func laneOf(roomID int64, n int) int {
+	return int(uint64(roomID) % uint64(n))
+}
+
+func EventsWorker(ws *WS) {
+	for {
+		select {
+		case <-ws.ctxStopWorkers.Done():
+			return
+		default:
+			ev, err := ws.services.Events.ReadEvent()
+			if err != nil {
+				if ws.fatalReadErr(err) {
 					return
 				}
-				if !errors.Is(err, context.DeadlineExceeded) {
-					ws.services.Log.Error(
-						"kafka worker read",
-						"error", err,
-					)
+				continue
+			}
+			if ev.Type == models.EventDisconnectUser {
+				ws.disconnectUser(ev.UID)
+				continue
+			}
+			broadcastEvent(ws, ev)
+		}
+	}
+}
+
+func UserEventsWorker(ws *WS) {
+	for {
+		select {
+		case <-ws.ctxStopWorkers.Done():
+			return
+		default:
+			ev, err := ws.services.UserEvents.ReadUserEvent()
+			if err != nil {
+				if ws.fatalReadErr(err) {
+					return
 				}
 				continue
 			}
-			broadcast(ws, msg)
+			sendToUser(ws, ev.UID, ev)
 		}
 	}
 }
+
+func (ws *WS) fatalReadErr(err error) bool {
+	if errors.Is(err, kafka.ErrGenerationEnded) || errors.Is(err, kafka.ErrGroupClosed) {
+		if ws.fatalErrorLoggedFlag.CompareAndSwap(false, true) {
+			ws.stopWorkers()
+			ws.services.Log.Error(
+				"kafka worker fatal error",
+				"error", err,
+			)
+		}
+		return true
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		ws.services.Log.Error(
+			"kafka worker read",
+			"error", err,
+		)
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+func TestKafkaWorkersKeepRoomOrder(t *testing.T) {
+	const (
+		rooms    = 6
+		messages = 150
+	)
+	broker := newStandInBroker()
+	server := startGateway(t, broker, newStandInBroker(), "gateway-1")
+	clients := make([]*websocket.Conn, rooms)
+	for i := range clients {
+		clients[i] = dial(t, server, int64(i+1))
+		enter(t, clients[i], int64(i+1))
+	}
+	for id := range messages {
+		for roomID := range int64(rooms) {
+			broker.Publish(t, &models.Message{
+				WSResponse: models.WSResponse{Type: "message"},
+				ID:         int64(id + 1),
+				RoomID:     roomID + 1,
+				UID:        100,
+				Text:       "hello",
+			})
+		}
+	}
+	for i, conn := range clients {
+		var last int64
+		for range messages {
+			frame := readFrame(t, conn)
+			if frame["Type"] != "message" {
+				t.Fatalf("room %d: unexpected frame %v", i+1, frame)
+			}
+			if roomID := int64(frame["RoomID"].(float64)); roomID != int64(i+1) {
+				t.Fatalf("room %d: got a message of room %d", i+1, roomID)
+			}
+			id := int64(frame["ID"].(float64))
+			if id != last+1 {
+				t.Fatalf("room %d: got message %d after %d", i+1, id, last)
+			}
+			last = id
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/responses"
+)
+
+// pollSession keeps a long-poll connection between requests. Frames wait
+// in the connection's send queue, and the session ends when nobody polls
+// it for poll_idle_timeout. Polled frames are numbered and kept in unacked
+// until a later poll acknowledges them, so a response lost on the way is
+// sent again. Only one poll runs at a time, which guards unacked.
+type pollSession struct {
+	h       *connectionHandler
+	touch   chan struct{}
+	busy    atomic.Bool
+	unacked []json.RawMessage
+	seq     int64
+}
+
+// pollResponse carries the frames numbered Seq-len(Frames)+1 to Seq.
+type pollResponse struct {
+	Session string            `json:"Session"`
+	Seq     int64             `json:"Seq"`
+	Frames  []json.RawMessage `json:"Frames"`
+}
+
+func (ws *WS) Poll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ws.isDraining() {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		uid, ok := ws.authenticate(w, r)
+		if !ok {
+			return
+		}
+		var ack int64
+		if raw := r.URL.Query().Get("ack"); raw != "" {
+			var err error
+			if ack, err = strconv.ParseInt(raw, 10, 64); err != nil || ack < 0 {
+				http.Error(w, "invalid ack", http.StatusBadRequest)
+				return
+			}
+		}
+		var s *pollSession
+		if id := r.URL.Query().Get("session"); id != "" {
+			ws.mu.RLock()
+			s = ws.polls[id]
+			ws.mu.RUnlock()
+			if s == nil || s.h.uid != uid {
+				http.Error(w, "unknown session", http.StatusNotFound)
+				return
+			}
+			go s.h.presenceHeartbeat()
+		} else {
+			roomIDs, lastSeen, ok := streamParams(w, r)
+			if !ok {
+				return
+			}
+			if s = ws.newPollSession(uid, r.RemoteAddr, roomIDs, lastSeen); s == nil {
+				http.Error(w, "too many connections", http.StatusTooManyRequests)
+				return
+			}
+		}
+		if !s.busy.CompareAndSwap(false, true) {
+			http.Error(w, "session is already polled", http.StatusConflict)
+			return
+		}
+		defer s.busy.Store(false)
+		s.touched()
+		defer s.touched()
+		http.NewResponseController(w).SetWriteDeadline(time.Now().Add(ws.cfg.PollTimeout + ws.cfg.WriteWait))
+		s.acknowledge(ack)
+		frames := s.poll(r.Context().Done())
+		responses.SendJSON(w, http.StatusOK, pollResponse{
+			Session: s.h.id,
+			Seq:     s.seq,
+			Frames:  frames,
+		})
+	}
+}
+
+func (ws *WS) newPollSession(uid int64, remoteAddr string, roomIDs []int64, lastSeen map[int64]int64) *pollSession {
+	s := &pollSession{
+		h:       newHandler(uid, ws, encodingJSON),
+		touch:   make(chan struct{}, 1),
+		unacked: make([]json.RawMessage, 0),
+	}
+	s.h.remoteAddr = remoteAddr
+	if !ws.register(s.h) {
+		return nil
+	}
+	ws.mu.Lock()
+	if s.h.ctx.Err() == nil {
+		ws.polls[s.h.id] = s
+	}
+	ws.mu.Unlock()
+	go s.h.subscribe(roomIDs, lastSeen)
+	go s.expire(ws.cfg.PollIdleTimeout)
+	return s
+}
+
+func (s *pollSession) touched() {
+	select {
+	case s.touch <- struct{}{}:
+	default:
+	}
+}
+
+func (s *pollSession) expire(idle time.Duration) {
+	defer func() { framesPending.Add(-int64(len(s.h.send))) }()
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.h.ctx.Done():
+			return
+		case <-s.touch:
+			timer.Reset(idle)
+		case <-timer.C:
+			if s.busy.Load() {
+				timer.Reset(idle)
+				continue
+			}
+			s.h.cancel()
+			return
+		}
+	}
+}
+
+// acknowledge drops the frames up to seq, which the client has received.
+func (s *pollSession) acknowledge(seq int64) {
+	first := s.seq - int64(len(s.unacked)) + 1
+	if seq < first {
+		return
+	}
+	n := min(seq-first+1, int64(len(s.unacked)))
+	s.unacked = s.unacked[n:]
+}
+
+// poll returns the unacknowledged frames together with new ones. With
+// nothing unacknowledged it waits up to poll_timeout for the first frame,
+// then takes every frame already queued, as long as unacked stays within
+// send_queue_size.
+func (s *pollSession) poll(gone <-chan struct{}) []json.RawMessage {
+	h := s.h
+	if len(s.unacked) == 0 {
+		timer := time.NewTimer(h.ws.cfg.PollTimeout)
+		defer timer.Stop()
+		select {
+		case v := <-h.send:
+			s.appendFrame(v)
+		case <-timer.C:
+		case <-gone:
+		case <-h.ctx.Done():
+			if farewell := h.goingAway(); farewell != nil {
+				if data, err := frameData(farewell); err == nil {
+					s.unacked = append(s.unacked, data)
+					s.seq++
+				}
+			}
+			return s.unacked
+		}
+	}
+	for len(s.unacked) < cap(h.send) {
+		select {
+		case v := <-h.send:
+			s.appendFrame(v)
+		default:
+			return s.unacked
+		}
+	}
+	return s.unacked
+}
+
+func (s *pollSession) appendFrame(v any) {
+	const op = "websocket.poll"
+	framesPending.Add(-1)
+	data, err := frameData(v)
+	if err != nil {
+		s.h.ws.services.Log.Error(
+			op,
+			"error", err,
+			"uid", s.h.uid,
+		)
+		return
+	}
+	s.unacked = append(s.unacked, data)
+	s.seq++
+}
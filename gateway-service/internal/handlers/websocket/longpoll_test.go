@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+)
+
+func newTestPollSession(t *testing.T) *pollSession {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Websocket.PollTimeout = 10 * time.Millisecond
+	services := &gateway.Services{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ws := newWS(&cfg.Websocket, services)
+	s := &pollSession{
+		h:     newHandler(1, ws, encodingJSON),
+		touch: make(chan struct{}, 1),
+	}
+	t.Cleanup(s.h.cancel)
+	return s
+}
+
+func TestPollResendsUntilAcknowledged(t *testing.T) {
+	s := newTestPollSession(t)
+	s.h.write(models.NewRoomIDResponse("joined", 1, 1))
+	s.h.write(models.NewRoomIDResponse("joined", 2, 1))
+
+	if frames := s.poll(nil); len(frames) != 2 || s.seq != 2 {
+		t.Fatalf("first poll: %d frames, seq %d", len(frames), s.seq)
+	}
+	if frames := s.poll(nil); len(frames) != 2 || s.seq != 2 {
+		t.Fatalf("poll without ack: %d frames, seq %d", len(frames), s.seq)
+	}
+
+	s.h.write(models.NewRoomIDResponse("joined", 3, 1))
+	s.acknowledge(1)
+	frames := s.poll(nil)
+	if len(frames) != 2 || s.seq != 3 {
+		t.Fatalf("poll after ack 1: %d frames, seq %d", len(frames), s.seq)
+	}
+	if string(frames[0]) != string(mustFrame(t, models.NewRoomIDResponse("joined", 2, 1))) {
+		t.Fatalf("first frame after ack 1 is %s", frames[0])
+	}
+
+	s.acknowledge(3)
+	if frames := s.poll(nil); len(frames) != 0 || s.seq != 3 {
+		t.Fatalf("poll after ack 3: %d frames, seq %d", len(frames), s.seq)
+	}
+}
+
+func TestPollIgnoresStaleAck(t *testing.T) {
+	s := newTestPollSession(t)
+	s.h.write(models.NewRoomIDResponse("joined", 1, 1))
+	s.poll(nil)
+	s.acknowledge(1)
+	s.h.write(models.NewRoomIDResponse("joined", 2, 1))
+	s.poll(nil)
+
+	s.acknowledge(1)
+	if len(s.unacked) != 1 {
+		t.Fatalf("stale ack dropped frames: %d left", len(s.unacked))
+	}
+}
+
+func mustFrame(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := frameData(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -1,7 +1,10 @@
 package websocket
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/gateway"
@@ -9,15 +12,41 @@ import (
 )
 
 type WS struct {
-	services *gateway.Services
-	cfg      *config.Websocket
+	services             *gateway.Services
+	cfg                  *config.Websocket
+	limits               *config.RateLimit
+	instanceID           string
+	handlersInRoom       map[int64]map[*connectionHandler]*subscription
+	handlers             map[*connectionHandler]struct{}
+	byUser               map[int64]map[*connectionHandler]struct{}
+	polls                map[string]*pollSession
+	mu                   sync.RWMutex
+	draining             bool
+	sends                sync.WaitGroup
+	ctxStopWorkers       context.Context
+	stopWorkers          context.CancelFunc
+	fatalErrorLoggedFlag atomic.Bool
+}
+
+func New(cfg *config.Config, s *gateway.Services) *WS {
+	ws := newWS(&cfg.Websocket, s)
+	ws.limits = &cfg.RateLimit
+	ws.instanceID = cfg.Kafka.InstanceID
+	StartKafkaWorkers(ws, cfg.Kafka.WorkerCount)
+	return ws
 }
 
 func newWS(cfg *config.Websocket, s *gateway.Services) *WS {
-	return &WS{
-		cfg:      cfg,
-		services: s,
+	ws := &WS{
+		cfg:            cfg,
+		services:       s,
+		handlersInRoom: make(map[int64]map[*connectionHandler]*subscription),
+		handlers:       make(map[*connectionHandler]struct{}),
+		byUser:         make(map[int64]map[*connectionHandler]struct{}),
+		polls:          make(map[string]*pollSession),
 	}
+	ws.ctxStopWorkers, ws.stopWorkers = context.WithCancel(context.Background())
+	return ws
 }
 
 func newUpgrader(cfg config.Websocket) *websocket.Upgrader {
@@ -25,6 +54,7 @@ func newUpgrader(cfg config.Websocket) *websocket.Upgrader {
 		WriteBufferSize:   cfg.WriteBufSize,
 		ReadBufferSize:    cfg.ReadBufSize,
 		EnableCompression: cfg.EnableCompression,
+		Subprotocols:      []string{SubprotocolJSON, SubprotocolProtobuf},
 	}
 	if cfg.CheckOrigin {
 		ws.CheckOrigin = func(r *http.Request) bool {
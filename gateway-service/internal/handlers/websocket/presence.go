@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
+)
+
+type presenceUpdate func(ctx context.Context) (*models.Presence, bool, error)
+
+// updatePresence runs one tracker call at a time per connection, so a late
+// heartbeat can't bring a connection back after its disconnect. A tracker
+// error is logged and returned.
+func (h *connectionHandler) updatePresence(op string, update presenceUpdate, final bool) error {
+	tracker := h.ws.services.Presence
+	if tracker == nil {
+		return nil
+	}
+	h.presenceMu.Lock()
+	defer h.presenceMu.Unlock()
+	if h.presenceDone {
+		return nil
+	}
+	h.presenceDone = final
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Presence)
+	defer cancel()
+	p, changed, err := update(ctx)
+	if err != nil {
+		h.ws.services.Log.Error(
+			op,
+			"error", err,
+			"uid", h.uid,
+		)
+		return err
+	}
+	if changed {
+		h.announcePresence(p)
+	}
+	return nil
+}
+
+func (h *connectionHandler) presenceConnect() {
+	h.updatePresence("websocket.presenceConnect", func(ctx context.Context) (*models.Presence, bool, error) {
+		return h.ws.services.Presence.Connect(ctx, h.uid, h.id)
+	}, false)
+}
+
+func (h *connectionHandler) presenceHeartbeat() {
+	h.updatePresence("websocket.presenceHeartbeat", func(ctx context.Context) (*models.Presence, bool, error) {
+		return h.ws.services.Presence.Heartbeat(ctx, h.uid, h.id)
+	}, false)
+}
+
+func (h *connectionHandler) presenceDisconnect() {
+	h.updatePresence("websocket.presenceDisconnect", func(ctx context.Context) (*models.Presence, bool, error) {
+		return h.ws.services.Presence.Disconnect(ctx, h.uid, h.id)
+	}, true)
+}
+
+func (h *connectionHandler) setPresence(status string) {
+	if status != models.StatusOnline && status != models.StatusAway {
+		h.replyErr(models.CodeInvalidStatus, "invalid status")
+		return
+	}
+	err := h.updatePresence("websocket.setPresence", func(ctx context.Context) (*models.Presence, bool, error) {
+		return h.ws.services.Presence.SetStatus(ctx, h.uid, h.id, status)
+	}, false)
+	if err != nil {
+		h.replyErr(models.CodeInternal, "internal error")
+		return
+	}
+	h.reply(models.NewStatusResponse(status))
+}
+
+func (h *connectionHandler) announcePresence(p *models.Presence) {
+	const op = "websocket.announcePresence"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	userIn, err := h.ws.services.Rooms.UserIn(ctx, &roomspb.UserInRequest{UID: h.uid})
+	if err != nil {
+		h.ws.services.Log.Error(
+			op,
+			"error", err,
+			"uid", h.uid,
+		)
+		return
+	}
+	if len(userIn.IDs) == 0 {
+		return
+	}
+	h.publish(models.NewPresenceEvent(p, userIn.IDs))
+}
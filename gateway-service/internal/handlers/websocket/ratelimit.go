@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+)
+
+// allow applies flood control to the request being routed. If Redis can't
+// be reached the request goes through. A connection that hits the limit
+// max_violations times within violation_window is closed.
+func (h *connectionHandler) allow() bool {
+	const op = "websocket.reader.allow"
+	limiter := h.ws.services.Limiter
+	if limiter == nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(h.ctx, h.ws.services.Timeouts.RateLimit)
+	defer cancel()
+	wait, err := limiter.Allow(ctx, h.uid, h.id)
+	if err != nil {
+		h.ws.services.Log.Warn(
+			op,
+			"error", err,
+			"uid", h.uid,
+		)
+		return true
+	}
+	if wait == 0 {
+		return true
+	}
+	h.reply(models.NewRateLimitedError(wait))
+	now := time.Now()
+	if now.Sub(h.violationsFrom) > h.ws.limits.ViolationWindow {
+		h.violationsFrom, h.violations = now, 0
+	}
+	h.violations++
+	if h.violations >= h.ws.limits.MaxViolations {
+		h.ws.services.Log.Warn(
+			op,
+			"message", "disconnecting flooding client",
+			"uid", h.uid,
+		)
+		h.disconnect(CloseRateLimited, "rate limit exceeded")
+	}
+	return false
+}
@@ -11,18 +11,22 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func (h *connectionHandler) reader() {
+func (h *connectionHandler) reader(lastSeen map[int64]int64) {
 	const op = "websocket.reader"
 	defer h.close()
 	defer h.cancel()
+	h.presenceConnect()
+	if len(lastSeen) > 0 {
+		h.enter(nil, lastSeen)
+	}
 	for {
 		select {
 		case <-h.ctx.Done():
 			return
 		default:
 		}
-		r := new(models.WSRequest)
-		if err := h.conn.ReadJSON(r); err != nil {
+		r, err := h.readRequest()
+		if err != nil {
 			if websocket.IsUnexpectedCloseError(err) {
 				h.ws.services.Log.Error(
 					op,
@@ -38,122 +42,296 @@ func (h *connectionHandler) reader() {
 
 func (h *connectionHandler) close() {
 	defer close(h.closeDone)
-	mu.Lock()
-	h.delRoomMember()
-	mu.Unlock()
-	h.conn.Close()
+	h.stopTyping(h.roomList()...)
+	h.presenceDisconnect()
+	h.ws.mu.Lock()
+	h.delRoomMember(h.roomList()...)
+	h.ws.removeLocked(h)
+	h.ws.mu.Unlock()
+	if h.conn != nil {
+		h.conn.Close()
+	}
 }
 
 func (h *connectionHandler) route(r *models.WSRequest) {
+	h.requestID = r.RequestID
+	defer func() { h.requestID = "" }()
+	if r.Type != "auth" && !h.allow() {
+		return
+	}
 	switch r.Type {
 	case "message":
 		h.sendMessage(&msgpb.SendRequest{
-			RoomID: h.roomID,
-			UID:    h.uid,
-			Type:   "message",
-			Text:   r.Text,
+			RoomID:   r.RoomID,
+			UID:      h.uid,
+			Type:     "message",
+			Text:     r.Text,
+			ClientID: r.ClientID,
+			ReplyTo:  r.ReplyTo,
 		})
-		return
 	case "enter":
-		h.enter(r.NewRoomID)
+		h.enter(r.Rooms(), r.LastSeen)
+	case "leave":
+		h.leave(r.Rooms())
+	case "typing":
+		h.typing(r.RoomID)
+	case "stop_typing":
+		h.stopTyping(r.RoomID)
+		h.reply(models.NewRoomIDResponse("stop_typing", r.RoomID, 0))
+	case "presence":
+		h.setPresence(r.Status)
+	case "read":
+		h.markRead(r.RoomID, r.MessageID)
+	case "edit":
+		h.editMessage(r.MessageID, r.Text)
+	case "delete":
+		h.deleteMessage(r.MessageID)
+	case "react":
+		h.react(r.MessageID, r.Emoji, true)
+	case "unreact":
+		h.react(r.MessageID, r.Emoji, false)
+	case "auth":
+		h.refreshToken(r.Token)
+	case "create_room":
+		h.createRoom(r.Name, r.IsPrivate)
+	case "join":
+		h.join(r.RoomID)
+	case "invite":
+		h.invite(r.RoomID, r.UID)
+	case "direct":
+		h.direct(r.UID)
+	case "room":
+		h.room(r.RoomID)
+	case "rooms":
+		h.userRooms()
+	case "history":
+		h.history(r.RoomID, r.LastID)
+	case "thread":
+		h.thread(r.RoomID, r.MessageID, r.LastID)
+	case "profile":
+		h.profile(r.UID)
+	case "change_name":
+		h.changeName(r.Name)
 	default:
-		h.SyncWriteJSON(models.NewWSError("invalid operation"))
+		h.replyErr(models.CodeInvalidOperation, "invalid operation")
 	}
 }
 
-func (h *connectionHandler) internalErr(op string, err error) {
-	h.SyncWriteJSON(models.NewWSError("internal error"))
-	h.ws.services.Log.Error(
-		op,
-		"error", err,
-	)
-}
-
 func (h *connectionHandler) sendMessage(msg *msgpb.SendRequest) {
 	const op = "websocket.reader.sendMessage"
-	if h.roomID == 0 {
-		h.SyncWriteJSON(models.NewWSError("not in room"))
+	if _, ok := h.rooms[msg.RoomID]; !ok {
+		h.replyErr(models.CodeNotInRoom, "not in room")
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
-	defer cancel()
-	resp, err := h.ws.services.Message.Send(ctx, msg)
+	if !h.ws.beginSend() {
+		h.replyErr(models.CodeUnavailable, "server is shutting down")
+		return
+	}
+	defer h.ws.sends.Done()
+	resp, err := h.trySend(msg)
 	if err != nil {
-		if status, ok := status.FromError(err); ok && status.Code() != codes.Internal {
-			h.SyncWriteJSON(models.NewWSError(status.Message()))
+		if st := status.Convert(err); st.Code() == codes.Unavailable {
+			if msg.ClientID == "" {
+				h.reply(models.NewFailedResponse(models.CodeUnavailable, st.Message(), msg.RoomID, ""))
+				return
+			}
+			if h.queueRetry(msg) {
+				return
+			}
 		}
-		h.internalErr(op, err)
+		h.grpcErr(op, err)
+		return
+	}
+	h.stopTyping(msg.RoomID)
+	h.reply(models.NewSentResponse(resp.ID, resp.Timestamp.AsTime(), msg.ClientID))
+}
+
+func (h *connectionHandler) markRead(roomID, messageID int64) {
+	const op = "websocket.reader.markRead"
+	if _, ok := h.rooms[roomID]; !ok {
+		h.replyErr(models.CodeNotInRoom, "not in room")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	resp, err := h.ws.services.Message.MarkRead(ctx, &msgpb.MarkReadRequest{
+		RoomID:    roomID,
+		UID:       h.uid,
+		MessageID: messageID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewReadResponse(roomID, messageID, resp.LastReadID))
+}
+
+func (h *connectionHandler) editMessage(messageID int64, text string) {
+	const op = "websocket.reader.editMessage"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	resp, err := h.ws.services.Message.Edit(ctx, &msgpb.EditRequest{
+		MessageID: messageID,
+		UID:       h.uid,
+		Text:      text,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewEditResponse(messageID, resp.RoomID, resp.EditedAt.AsTime()))
+}
+
+func (h *connectionHandler) deleteMessage(messageID int64) {
+	const op = "websocket.reader.deleteMessage"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	resp, err := h.ws.services.Message.Delete(ctx, &msgpb.DeleteRequest{
+		MessageID: messageID,
+		UID:       h.uid,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewDeleteResponse(messageID, resp.RoomID, resp.DeletedAt.AsTime()))
+}
+
+func (h *connectionHandler) react(messageID int64, emoji string, add bool) {
+	const op = "websocket.reader.react"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	req := &msgpb.ReactionRequest{
+		MessageID: messageID,
+		UID:       h.uid,
+		Emoji:     emoji,
+	}
+	typ := "react"
+	var resp *msgpb.ReactionResponse
+	var err error
+	if add {
+		resp, err = h.ws.services.Message.AddReaction(ctx, req)
+	} else {
+		typ = "unreact"
+		resp, err = h.ws.services.Message.RemoveReaction(ctx, req)
+	}
+	if err != nil {
+		h.grpcErr(op, err)
 		return
 	}
-	h.SyncWriteJSON(models.NewSentResponse(resp.ID, resp.Timestamp.AsTime()))
+	h.reply(models.NewReactionResponse(typ, messageID, resp.RoomID, emoji, resp.Count))
 }
 
-func (h *connectionHandler) enter(newRoomID int64) {
+func (h *connectionHandler) enter(roomIDs []int64, lastSeen map[int64]int64) {
 	const op = "websocket.reader.enter"
-	if err := h.validateEnter(newRoomID); err != nil {
-		if err.Error == "internal error" {
-			h.ws.services.Log.Error(
-				op,
-				"error", err,
-			)
+	for roomID := range lastSeen {
+		roomIDs = append(roomIDs, roomID)
+	}
+	if len(roomIDs) == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+		defer cancel()
+		userIn, err := h.ws.services.Rooms.UserIn(ctx, &roomspb.UserInRequest{UID: h.uid})
+		if err != nil {
+			h.internalErr(op, err)
+			return
 		}
-		h.SyncWriteJSON(err)
+		roomIDs = userIn.IDs
+	} else if err := h.validateEnter(roomIDs); err != nil {
+		h.reply(err)
 		return
 	}
-	mu.Lock()
-	h.delRoomMember()
-	h.setRoomMember(newRoomID)
-	mu.Unlock()
-	h.SyncWriteJSON(models.NewEnterResponse())
+	backfills := make(map[int64]*subscription)
+	h.ws.mu.Lock()
+	for _, roomID := range roomIDs {
+		if _, ok := h.rooms[roomID]; ok {
+			continue
+		}
+		sub := &subscription{}
+		if _, ok := lastSeen[roomID]; ok {
+			sub.backfilling = true
+			backfills[roomID] = sub
+		}
+		h.setRoomMember(roomID, sub)
+	}
+	h.ws.mu.Unlock()
+	h.reply(models.NewEnterResponse(h.roomList()))
+	for roomID, sub := range backfills {
+		h.backfill(roomID, lastSeen[roomID], sub)
+	}
 }
 
-func (h *connectionHandler) validateEnter(newRoomID int64) *models.WSError {
-	if h.roomID == newRoomID {
-		return models.NewWSError("already in room")
-	}
-	if newRoomID < 0 {
-		return models.NewWSError("invalid room id")
+func (h *connectionHandler) leave(roomIDs []int64) {
+	if len(roomIDs) == 0 {
+		roomIDs = h.roomList()
 	}
+	h.stopTyping(roomIDs...)
+	h.ws.mu.Lock()
+	h.delRoomMember(roomIDs...)
+	h.ws.mu.Unlock()
+	h.reply(models.NewLeaveResponse(h.roomList()))
+}
+
+func (h *connectionHandler) validateEnter(roomIDs []int64) *models.WSError {
+	const op = "websocket.reader.validateEnter"
 	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
 	defer cancel()
-	if newRoomID != 0 {
+	for _, roomID := range roomIDs {
+		if roomID <= 0 {
+			return models.NewWSError(models.CodeInvalidRoomID, "invalid room id")
+		}
+		if _, ok := h.rooms[roomID]; ok {
+			continue
+		}
 		isMember, err := h.ws.services.Rooms.IsMember(ctx, &roomspb.IsMemberRequest{
 			UID:    h.uid,
-			RoomID: newRoomID,
+			RoomID: roomID,
 		})
 		if err != nil {
-			return models.NewWSError("internal error")
+			h.ws.services.Log.Error(
+				op,
+				"error", err,
+				"uid", h.uid,
+			)
+			return models.NewWSError(models.CodeInternal, "internal error")
 		}
 		if !isMember.IsMember {
-			return models.NewWSError("not room member")
+			return models.NewWSError(models.CodeNotMember, "not room member")
 		}
 	}
 	return nil
 }
 
-func (h *connectionHandler) setRoomMember(roomID int64) {
-	handlers, ok := handlersInRoom[roomID]
-	if !ok {
-		m := make(map[*connectionHandler]struct{})
-		m[h] = struct{}{}
-		handlersInRoom[roomID] = m
-	} else {
-		handlers[h] = struct{}{}
+func (h *connectionHandler) roomList() []int64 {
+	rooms := make([]int64, 0, len(h.rooms))
+	for roomID := range h.rooms {
+		rooms = append(rooms, roomID)
 	}
-	h.roomID = roomID
+	return rooms
 }
 
-func (h *connectionHandler) delRoomMember() {
-	if h.roomID == 0 {
-		return
+func (h *connectionHandler) setRoomMember(roomID int64, sub *subscription) {
+	handlers, ok := h.ws.handlersInRoom[roomID]
+	if !ok {
+		handlers = make(map[*connectionHandler]*subscription)
+		h.ws.handlersInRoom[roomID] = handlers
 	}
-	handlers, ok := handlersInRoom[h.roomID]
-	if ok {
-		if len(handlers) == 1 {
-			delete(handlersInRoom, h.roomID)
-		} else {
-			delete(handlers, h)
+	handlers[h] = sub
+	h.rooms[roomID] = sub
+}
+
+func (h *connectionHandler) delRoomMember(roomIDs ...int64) {
+	for _, roomID := range roomIDs {
+		if _, ok := h.rooms[roomID]; !ok {
+			continue
+		}
+		handlers, ok := h.ws.handlersInRoom[roomID]
+		if ok {
+			if len(handlers) == 1 {
+				delete(h.ws.handlersInRoom, roomID)
+			} else {
+				delete(handlers, h)
+			}
 		}
+		delete(h.rooms, roomID)
 	}
-	h.roomID = 0
 }
@@ -0,0 +1,84 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *connectionHandler) trySend(msg *msgpb.SendRequest) (*msgpb.SendResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	return h.ws.services.Message.Send(ctx, msg)
+}
+
+// queueRetry keeps a send that failed because message-service was
+// unavailable and retries it in the background. Only sends with a ClientID
+// are kept, as it is what stops a retry of a send that did get stored from
+// storing it twice. At most retry_queue_size sends of a connection wait at
+// once; it reports false when there is no room left, so the caller answers
+// with the error.
+func (h *connectionHandler) queueRetry(msg *msgpb.SendRequest) bool {
+	if h.retrying.Add(1) > int32(h.ws.cfg.RetryQueueSize) {
+		h.retrying.Add(-1)
+		return false
+	}
+	h.reply(models.NewQueuedResponse(msg.RoomID, msg.ClientID))
+	go h.retrySend(msg, h.requestID)
+	return true
+}
+
+// retrySend makes up to retry_attempts more attempts, doubling the pause
+// from retry_backoff up to retry_max_backoff, and answers sent or failed.
+// It gives up silently once the connection is closed: the client never got
+// sent, so it resends after reconnecting.
+func (h *connectionHandler) retrySend(msg *msgpb.SendRequest, requestID string) {
+	const op = "websocket.retrySend"
+	defer h.retrying.Add(-1)
+	cfg := h.ws.cfg
+	backoff := cfg.RetryBackoff
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if !h.ws.beginSend() {
+			h.replyTo(requestID, models.NewFailedResponse(
+				models.CodeUnavailable, "server is shutting down", msg.RoomID, msg.ClientID,
+			))
+			return
+		}
+		resp, err := h.trySend(msg)
+		h.ws.sends.Done()
+		if err == nil {
+			h.stopTyping(msg.RoomID)
+			h.replyTo(requestID, models.NewSentResponse(resp.ID, resp.Timestamp.AsTime(), msg.ClientID))
+			return
+		}
+		st := status.Convert(err)
+		if st.Code() == codes.Unavailable && attempt < cfg.RetryAttempts {
+			backoff = min(2*backoff, cfg.RetryMaxBackoff)
+			timer.Reset(backoff)
+			continue
+		}
+		code, ok := grpcToWS[st.Code()]
+		text := st.Message()
+		if !ok {
+			h.ws.services.Log.Error(
+				op,
+				"error", err,
+				"uid", h.uid,
+			)
+			code, text = models.CodeInternal, "internal error"
+		}
+		h.replyTo(requestID, models.NewFailedResponse(code, text, msg.RoomID, msg.ClientID))
+		return
+	}
+}
@@ -0,0 +1,211 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	msgpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/message"
+	roomspb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/rooms"
+	userpb "github.com/P3rCh1/chat-server/gateway-service/pkg/proto/gen/go/user"
+)
+
+// The operations below mirror the HTTP API, so a client can work over a
+// single socket.
+
+func (h *connectionHandler) createRoom(name string, isPrivate bool) {
+	const op = "websocket.reader.createRoom"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	resp, err := h.ws.services.Rooms.Create(ctx, &roomspb.CreateRequest{
+		Name:      name,
+		UID:       h.uid,
+		IsPrivate: isPrivate,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewRoomIDResponse("create_room", resp.RoomID, 0))
+}
+
+func (h *connectionHandler) join(roomID int64) {
+	const op = "websocket.reader.join"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	_, err := h.ws.services.Rooms.Join(ctx, &roomspb.JoinRequest{
+		UID:    h.uid,
+		RoomID: roomID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewRoomIDResponse("join", roomID, 0))
+}
+
+func (h *connectionHandler) invite(roomID, uid int64) {
+	const op = "websocket.reader.invite"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	_, err := h.ws.services.Rooms.Invite(ctx, &roomspb.InviteRequest{
+		CreatorUID: h.uid,
+		UID:        uid,
+		RoomID:     roomID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewRoomIDResponse("invite", roomID, uid))
+}
+
+func (h *connectionHandler) room(roomID int64) {
+	const op = "websocket.reader.room"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	resp, err := h.ws.services.Rooms.Get(ctx, &roomspb.GetRequest{RoomID: roomID})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(&models.RoomResponse{
+		WSResponse: models.WSResponse{Type: "room"},
+		RoomID:     resp.RoomID,
+		Name:       resp.Name,
+		CreatorUID: resp.CreatorUID,
+		IsPrivate:  resp.IsPrivate,
+		IsDirect:   resp.IsDirect,
+		CreatedAt:  resp.CreatedAt.AsTime(),
+	})
+}
+
+func (h *connectionHandler) userRooms() {
+	const op = "websocket.reader.userRooms"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	userIn, err := h.ws.services.Rooms.UserIn(ctx, &roomspb.UserInRequest{UID: h.uid})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	unread, err := h.ws.services.Message.Unread(ctx, &msgpb.UnreadRequest{
+		UID:     h.uid,
+		RoomIDs: userIn.IDs,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	directs, err := h.ws.services.DirectRooms(context.Background(), h.uid)
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewUserRoomsResponse(userIn.IDs, directs, unread.Counts))
+}
+
+func (h *connectionHandler) direct(peerUID int64) {
+	const op = "websocket.reader.direct"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Rooms)
+	defer cancel()
+	resp, err := h.ws.services.Rooms.GetOrCreateDirect(ctx, &roomspb.DirectRequest{
+		UID:     h.uid,
+		PeerUID: peerUID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewDirectResponse(resp.RoomID, peerUID, resp.Created))
+}
+
+// history pages back through a room the user is a member of, starting
+// before lastID, or from the newest message when it is zero.
+func (h *connectionHandler) history(roomID, lastID int64) {
+	const op = "websocket.reader.history"
+	if wsErr := h.validateEnter([]int64{roomID}); wsErr != nil {
+		h.reply(wsErr)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	resp, err := h.ws.services.Message.Get(ctx, &msgpb.GetRequest{
+		RoomID: roomID,
+		LastID: lastID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	messages := make([]*models.Message, len(resp.Messages))
+	for i, m := range resp.Messages {
+		messages[i] = messageFromProto(m)
+	}
+	h.reply(models.NewHistoryResponse(roomID, messages))
+}
+
+// thread pages back through the replies to root like history does through
+// a room.
+func (h *connectionHandler) thread(roomID, root, lastID int64) {
+	const op = "websocket.reader.thread"
+	if wsErr := h.validateEnter([]int64{roomID}); wsErr != nil {
+		h.reply(wsErr)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.Message)
+	defer cancel()
+	resp, err := h.ws.services.Message.GetThread(ctx, &msgpb.GetThreadRequest{
+		RoomID:     roomID,
+		ThreadRoot: root,
+		LastID:     lastID,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	messages := make([]*models.Message, len(resp.Messages))
+	for i, m := range resp.Messages {
+		messages[i] = messageFromProto(m)
+	}
+	h.reply(models.NewThreadResponse(roomID, root, messages))
+}
+
+// profile returns the given user's profile, or the caller's own when uid
+// is zero.
+func (h *connectionHandler) profile(uid int64) {
+	const op = "websocket.reader.profile"
+	if uid == 0 {
+		uid = h.uid
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.User)
+	defer cancel()
+	resp, err := h.ws.services.User.Profile(ctx, &userpb.ProfileRequest{UID: uid})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(&models.ProfileResponse{
+		WSResponse: models.WSResponse{Type: "profile"},
+		UID:        resp.UID,
+		Username:   resp.Username,
+		Email:      resp.Email,
+		CreatedAt:  resp.CreatedAt.AsTime(),
+	})
+}
+
+func (h *connectionHandler) changeName(name string) {
+	const op = "websocket.reader.changeName"
+	ctx, cancel := context.WithTimeout(context.Background(), h.ws.services.Timeouts.User)
+	defer cancel()
+	_, err := h.ws.services.User.ChangeName(ctx, &userpb.ChangeNameRequest{
+		UID:     h.uid,
+		NewName: name,
+	})
+	if err != nil {
+		h.grpcErr(op, err)
+		return
+	}
+	h.reply(models.NewNameResponse(name))
+}
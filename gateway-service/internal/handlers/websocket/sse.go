@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+func (ws *WS) Events() http.HandlerFunc {
+	const op = "websocket.Events"
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ws.isDraining() {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		roomIDs, lastSeen, ok := streamParams(w, r)
+		if !ok {
+			return
+		}
+		uid, ok := ws.authenticate(w, r)
+		if !ok {
+			return
+		}
+		h := newHandler(uid, ws, encodingJSON)
+		h.remoteAddr = r.RemoteAddr
+		if !ws.register(h) {
+			http.Error(w, "too many connections", http.StatusTooManyRequests)
+			return
+		}
+		go h.subscribe(roomIDs, lastSeen)
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			ws.services.Log.Error(op, "error", err)
+			h.cancel()
+			<-h.closeDone
+			return
+		}
+		h.streamEvents(w, rc, r.Context().Done())
+		<-h.closeDone
+	}
+}
+
+// streamEvents is the writer of a Server-Sent Events connection. Every frame
+// is one "data:" event with the same JSON the websocket would carry.
+func (h *connectionHandler) streamEvents(w io.Writer, rc *http.ResponseController, gone <-chan struct{}) {
+	const op = "websocket.streamEvents"
+	defer h.cancel()
+	defer func() { framesPending.Add(-int64(len(h.send))) }()
+	ticker := time.NewTicker(h.ws.cfg.PingPeriod)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-h.ctx.Done():
+			if farewell := h.goingAway(); farewell != nil {
+				if data, err := frameData(farewell); err == nil {
+					rc.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+					fmt.Fprintf(w, "data: %s\n\n", data)
+					rc.Flush()
+				}
+			}
+			return
+		case <-gone:
+			return
+		case v := <-h.send:
+			framesPending.Add(-1)
+			data, encErr := frameData(v)
+			if encErr != nil {
+				h.ws.services.Log.Error(
+					op,
+					"error", encErr,
+					"uid", h.uid,
+				)
+				continue
+			}
+			rc.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+		case <-ticker.C:
+			rc.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+			_, err = io.WriteString(w, ": ping\n\n")
+			go h.presenceHeartbeat()
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			h.ws.services.Log.Warn(
+				op,
+				"error", err,
+				"uid", h.uid,
+			)
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// subscribe plays the reader's part for transports that have no inbound
+// channel: the rooms are chosen once, when the stream is opened, and the
+// connection is cleaned up after it ends.
+func (h *connectionHandler) subscribe(roomIDs []int64, lastSeen map[int64]int64) {
+	defer h.close()
+	h.presenceConnect()
+	h.enter(roomIDs, lastSeen)
+	<-h.ctx.Done()
+}
+
+// streamParams reads the rooms to subscribe to from "rooms=1,2" and the
+// resume point from last_seen; without both every room of the user is
+// entered.
+func streamParams(w http.ResponseWriter, r *http.Request) ([]int64, map[int64]int64, bool) {
+	roomIDs, err := parseRooms(r.URL.Query().Get("rooms"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	lastSeen, err := parseLastSeen(r.URL.Query().Get("last_seen"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return roomIDs, lastSeen, true
+}
+
+func parseRooms(raw string) ([]int64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	roomIDs := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		roomID, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || roomID <= 0 {
+			return nil, fmt.Errorf("invalid rooms entry %q", part)
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+	return roomIDs, nil
+}
+
+// frameData returns the JSON of a queued frame for streams without a
+// websocket.
+func frameData(v any) ([]byte, error) {
+	if f, ok := v.(*frame); ok {
+		return f.data, nil
+	}
+	_, data, err := encode(encodingJSON, v)
+	return data, err
+}
@@ -0,0 +1,71 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+)
+
+// typingState expires on its own: a refresh only moves the deadline, and
+// the timer re-arms itself until the deadline passes.
+type typingState struct {
+	timer    *time.Timer
+	deadline time.Time
+}
+
+func (h *connectionHandler) typing(roomID int64) {
+	if _, ok := h.rooms[roomID]; !ok {
+		h.replyErr(models.CodeNotInRoom, "not in room")
+		return
+	}
+	h.reply(models.NewRoomIDResponse("typing", roomID, 0))
+	h.typingMu.Lock()
+	defer h.typingMu.Unlock()
+	deadline := time.Now().Add(h.ws.cfg.TypingTimeout)
+	if state, ok := h.typingIn[roomID]; ok {
+		state.deadline = deadline
+		return
+	}
+	state := &typingState{deadline: deadline}
+	state.timer = time.AfterFunc(h.ws.cfg.TypingTimeout, func() {
+		h.typingMu.Lock()
+		defer h.typingMu.Unlock()
+		if h.typingIn[roomID] != state {
+			return
+		}
+		if left := time.Until(state.deadline); left > 0 {
+			state.timer.Reset(left)
+			return
+		}
+		delete(h.typingIn, roomID)
+		h.publish(models.NewTypingEvent(false, roomID, h.uid))
+	})
+	h.typingIn[roomID] = state
+	h.publish(models.NewTypingEvent(true, roomID, h.uid))
+}
+
+func (h *connectionHandler) stopTyping(roomIDs ...int64) {
+	h.typingMu.Lock()
+	defer h.typingMu.Unlock()
+	for _, roomID := range roomIDs {
+		state, ok := h.typingIn[roomID]
+		if !ok {
+			continue
+		}
+		state.timer.Stop()
+		delete(h.typingIn, roomID)
+		h.publish(models.NewTypingEvent(false, roomID, h.uid))
+	}
+}
+
+func (h *connectionHandler) publish(ev *models.Event) {
+	const op = "websocket.publish"
+	if err := h.ws.services.Producer.Send(context.Background(), ev); err != nil {
+		h.ws.services.Log.Error(
+			op,
+			"error", err,
+			"type", ev.Type,
+		)
+	}
+}
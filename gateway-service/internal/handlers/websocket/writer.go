@@ -1,70 +1,215 @@
 package websocket
 
 import (
+	"expvar"
 	"time"
 
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
 	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
 	"github.com/gorilla/websocket"
 )
 
-func (h *connectionHandler) pinger() {
+const (
+	CloseSlowConsumer    = 4000
+	CloseTokenExpired    = 4001
+	CloseTokenRevoked    = 4002
+	CloseUnauthenticated = 4003
+	CloseRateLimited     = 4004
+	CloseTooManyConns    = 4005
+	CloseDisconnected    = 4006
+)
+
+var (
+	framesQueued  = expvar.NewInt("ws_frames_queued")
+	framesPending = expvar.NewInt("ws_frames_pending")
+	framesDropped = expvar.NewInt("ws_frames_dropped")
+	slowConsumers = expvar.NewInt("ws_slow_consumers_disconnected")
+)
+
+func (h *connectionHandler) writer() {
+	const op = "websocket.writer"
+	defer h.conn.Close()
 	defer h.cancel()
 	ticker := time.NewTicker(h.ws.cfg.PingPeriod)
 	defer ticker.Stop()
 	failedPings := 0
 	for {
-		var err error
 		select {
 		case <-h.ctx.Done():
+			framesPending.Add(-int64(len(h.send)))
+			if farewell := h.goingAway(); farewell != nil {
+				h.conn.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+				h.writeValue(farewell)
+			}
+			h.conn.WriteControl(websocket.CloseMessage, h.closeMessage(), time.Now().Add(h.ws.cfg.WriteWait))
 			return
-		default:
+		case v := <-h.send:
+			framesPending.Add(-1)
+			h.conn.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+			var err error
+			if f, ok := v.(*frame); ok {
+				err = h.conn.WritePreparedMessage(f.prepared)
+			} else {
+				err = h.writeValue(v)
+			}
+			if err != nil {
+				h.ws.services.Log.Warn(
+					op,
+					"error", err,
+					"uid", h.uid,
+				)
+				return
+			}
+		case <-ticker.C:
+			h.conn.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
+			if err := h.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				failedPings++
+				if failedPings > h.ws.cfg.MaxFailedPings {
+					return
+				}
+			} else {
+				failedPings = 0
+				go h.presenceHeartbeat()
+			}
 		}
+	}
+}
+
+func (h *connectionHandler) writeValue(v any) error {
+	msgType, data, err := encode(h.encoding, v)
+	if err != nil {
+		return err
+	}
+	return h.conn.WriteMessage(msgType, data)
+}
+
+func (h *connectionHandler) write(v any) {
+	for {
 		select {
-		case <-ticker.C:
-			err = h.SyncWriteMessage(websocket.PingMessage, nil)
 		case <-h.ctx.Done():
 			return
+		case h.send <- v:
+			framesQueued.Add(1)
+			framesPending.Add(1)
+			return
+		default:
 		}
-		if err != nil {
-			failedPings++
-			if failedPings > h.ws.cfg.MaxFailedPings {
-				return
-			}
-		} else {
-			failedPings = 0
+		framesDropped.Add(1)
+		if h.ws.cfg.OverflowPolicy == config.OverflowDisconnect {
+			slowConsumers.Add(1)
+			h.disconnect(CloseSlowConsumer, "slow consumer")
+			return
 		}
+		select {
+		case <-h.send:
+			framesPending.Add(-1)
+		default:
+		}
+	}
+}
+
+// writeWait queues v like write, but waits for room in the queue instead of
+// applying the overflow policy. It reports false once the connection is gone.
+func (h *connectionHandler) writeWait(v any) bool {
+	select {
+	case <-h.ctx.Done():
+		return false
+	case h.send <- v:
+		framesQueued.Add(1)
+		framesPending.Add(1)
+		return true
 	}
 }
 
+func (h *connectionHandler) disconnect(code int, text string) {
+	h.closeOnce.Do(func() {
+		h.closeMsg = websocket.FormatCloseMessage(code, text)
+	})
+	h.cancel()
+}
+
+func (h *connectionHandler) closeMessage() []byte {
+	h.closeOnce.Do(func() {
+		h.closeMsg = websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	})
+	return h.closeMsg
+}
+
 func broadcast(ws *WS, msg *models.Message) {
 	const op = "websocket.writer.broadcast"
-	mu.RLock()
-	defer mu.RUnlock()
-	handlers, ok := handlersInRoom[msg.RoomID]
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	handlers, ok := ws.handlersInRoom[msg.RoomID]
 	if !ok {
 		return
 	}
-	for h := range handlers {
-		if err := h.SyncWriteJSON(msg); err != nil {
-			ws.services.Log.Warn(
+	f := &frames{v: msg.Outgoing()}
+	for h, sub := range handlers {
+		fr, err := f.get(h.encoding)
+		if err != nil {
+			ws.services.Log.Error(
 				op,
 				"error", err,
 				"messageID", msg.ID,
 			)
+			continue
 		}
+		h.deliver(sub, msg, fr)
 	}
 }
 
-func (h *connectionHandler) SyncWriteJSON(v any) error {
-	h.writeMutex.Lock()
-	defer h.writeMutex.Unlock()
-	h.conn.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
-	return h.conn.WriteJSON(v)
+// broadcastEvent delivers an event once per connection, even when it shares
+// several of the event's rooms. Room lists are routing only and are not
+// passed on to clients.
+func broadcastEvent(ws *WS, ev *models.Event) {
+	const op = "websocket.writer.broadcastEvent"
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	targets := make(map[*connectionHandler]struct{})
+	for _, roomID := range ev.Rooms() {
+		for h := range ws.handlersInRoom[roomID] {
+			if h.uid != ev.UID {
+				targets[h] = struct{}{}
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	out := *ev
+	out.RoomIDs = nil
+	f := &frames{v: &out}
+	for h := range targets {
+		fr, err := f.get(h.encoding)
+		if err != nil {
+			ws.services.Log.Error(
+				op,
+				"error", err,
+				"type", ev.Type,
+			)
+			continue
+		}
+		h.write(fr)
+	}
 }
 
-func (h *connectionHandler) SyncWriteMessage(messageType int, data []byte) error {
-	h.writeMutex.Lock()
-	defer h.writeMutex.Unlock()
-	h.conn.SetWriteDeadline(time.Now().Add(h.ws.cfg.WriteWait))
-	return h.conn.WriteMessage(messageType, data)
+// sendToUser writes v to every connection of the user, whatever rooms they
+// have entered.
+func sendToUser(ws *WS, uid int64, v any) {
+	const op = "websocket.writer.sendToUser"
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	f := &frames{v: v}
+	for h := range ws.byUser[uid] {
+		fr, err := f.get(h.encoding)
+		if err != nil {
+			ws.services.Log.Error(
+				op,
+				"error", err,
+				"uid", uid,
+			)
+			continue
+		}
+		h.write(fr)
+	}
 }
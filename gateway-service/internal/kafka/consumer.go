@@ -10,20 +10,74 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+type Reader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
 type Consumer struct {
-	r       *kafka.Reader
+	r       Reader
 	timeout time.Duration
 }
 
 func NewConsumer(cfg config.Kafka) *Consumer {
+	return NewConsumerFromReader(newReader(ReaderConfig(cfg)), cfg.Timeout)
+}
+
+func NewConsumerFromReader(r Reader, timeout time.Duration) *Consumer {
 	return &Consumer{
-		r: kafka.NewReader(kafka.ReaderConfig{
-			Brokers: cfg.Brokers,
-			GroupID: cfg.GroupID,
-			Topic:   cfg.Topic,
-		}),
-		timeout: cfg.Timeout,
+		r:       r,
+		timeout: timeout,
+	}
+}
+
+// newReader reads without a consumer group from the tail of every partition
+// when cfg has no GroupID.
+func newReader(cfg kafka.ReaderConfig) Reader {
+	if cfg.GroupID == "" {
+		return newTailReader(cfg)
+	}
+	return kafka.NewReader(cfg)
+}
+
+// ReaderConfig shares one consumer group between the gateway instances in
+// group mode. In broadcast mode it has no group, so each instance reads every
+// room event from the tail of the topic.
+func ReaderConfig(cfg config.Kafka) kafka.ReaderConfig {
+	readerCfg := kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		Topic:   cfg.Topic,
+	}
+	if cfg.FanOut == config.FanOutGroup {
+		readerCfg.GroupID = cfg.GroupID
 	}
+	return readerCfg
+}
+
+// EventsReaderConfig never has a group: ephemeral gateway events must reach
+// every instance whatever the fan_out mode is.
+func EventsReaderConfig(cfg config.Kafka) kafka.ReaderConfig {
+	return kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		Topic:   cfg.EventsTopic,
+	}
+}
+
+func NewEventsConsumer(cfg config.Kafka) *Consumer {
+	return NewConsumerFromReader(newReader(EventsReaderConfig(cfg)), cfg.Timeout)
+}
+
+// UserEventsReaderConfig has no group either: a user's connections may be
+// spread over every instance.
+func UserEventsReaderConfig(cfg config.Kafka) kafka.ReaderConfig {
+	return kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		Topic:   cfg.UserEventsTopic,
+	}
+}
+
+func NewUserEventsConsumer(cfg config.Kafka) *Consumer {
+	return NewConsumerFromReader(newReader(UserEventsReaderConfig(cfg)), cfg.Timeout)
 }
 
 func (c *Consumer) Read() (*models.Message, error) {
@@ -38,3 +92,33 @@ func (c *Consumer) Read() (*models.Message, error) {
 	}
 	return msg, nil
 }
+
+func (c *Consumer) ReadEvent() (*models.Event, error) {
+	msgKafka, err := c.r.ReadMessage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ev := new(models.Event)
+	err = json.Unmarshal(msgKafka.Value, ev)
+	if err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (c *Consumer) ReadUserEvent() (*models.UserEvent, error) {
+	msgKafka, err := c.r.ReadMessage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ev := new(models.UserEvent)
+	err = json.Unmarshal(msgKafka.Value, ev)
+	if err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (c *Consumer) Close() error {
+	return c.r.Close()
+}
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/segmentio/kafka-go"
+)
+
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type Producer struct {
+	w Writer
+}
+
+func NewProducer(cfg config.Kafka, log *slog.Logger) *Producer {
+	return NewProducerFromWriter(kafka.NewWriter(kafka.WriterConfig{
+		Brokers:      cfg.Brokers,
+		Topic:        cfg.EventsTopic,
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: 10 * time.Millisecond,
+		Async:        true,
+		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...any) {
+			log.Error("kafka producer", "error", fmt.Sprintf(msg, args...))
+		}),
+	}))
+}
+
+func NewProducerFromWriter(w Writer) *Producer {
+	return &Producer{w: w}
+}
+
+func (p *Producer) Send(ctx context.Context, ev *models.Event) error {
+	bytes, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	key := ev.RoomID
+	if key == 0 {
+		key = ev.UID
+	}
+	return p.w.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(strconv.FormatInt(key, 10)),
+		Value: bytes,
+	})
+}
+
+func (p *Producer) Close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	lookupBackoff    = time.Second
+	maxLookupBackoff = 30 * time.Second
+)
+
+// tailReader reads every partition of a topic without a consumer group,
+// starting at the newest offset of each. Nothing is committed, so no group
+// is left behind when an instance goes away, and a restarted instance starts
+// at the tail again instead of replaying what it missed. Partitions are
+// looked up once: ones added to the topic later need a restart.
+type tailReader struct {
+	cfg    kafka.ReaderConfig
+	msgs   chan kafka.Message
+	errs   chan error
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newTailReader(cfg kafka.ReaderConfig) *tailReader {
+	r := &tailReader{
+		cfg:  cfg,
+		msgs: make(chan kafka.Message),
+		errs: make(chan error, 1),
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	go r.start()
+	return r
+}
+
+// start looks the partitions up, retrying until the brokers answer, and
+// reads each of them in its own goroutine, which keeps the order within a
+// partition.
+func (r *tailReader) start() {
+	backoff := lookupBackoff
+	for {
+		partitions, err := lookupPartitions(r.ctx, r.cfg.Brokers, r.cfg.Topic)
+		if err == nil {
+			for _, p := range partitions {
+				cfg := r.cfg
+				cfg.Partition = p.ID
+				go r.readPartition(kafka.NewReader(cfg))
+			}
+			return
+		}
+		r.fail(err)
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxLookupBackoff)
+	}
+}
+
+func (r *tailReader) readPartition(pr *kafka.Reader) {
+	defer pr.Close()
+	if err := pr.SetOffset(kafka.LastOffset); err != nil {
+		r.fail(err)
+		return
+	}
+	for {
+		msg, err := pr.ReadMessage(r.ctx)
+		if err != nil {
+			if r.ctx.Err() == nil {
+				r.fail(fmt.Errorf("partition %d stopped: %w", pr.Config().Partition, err))
+			}
+			return
+		}
+		select {
+		case r.msgs <- msg:
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// fail hands err to the next ReadMessage call, unless one is already waiting.
+func (r *tailReader) fail(err error) {
+	select {
+	case r.errs <- err:
+	default:
+	}
+}
+
+func (r *tailReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if r.ctx.Err() != nil {
+		return kafka.Message{}, io.EOF
+	}
+	select {
+	case msg := <-r.msgs:
+		return msg, nil
+	case err := <-r.errs:
+		return kafka.Message{}, err
+	case <-r.ctx.Done():
+		return kafka.Message{}, io.EOF
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (r *tailReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+func lookupPartitions(ctx context.Context, brokers []string, topic string) ([]kafka.Partition, error) {
+	err := fmt.Errorf("no brokers to look up topic %q", topic)
+	for _, broker := range brokers {
+		partitions, lookupErr := kafka.DefaultDialer.LookupPartitions(ctx, "tcp", broker, topic)
+		if lookupErr != nil {
+			err = fmt.Errorf("look up partitions of %q: %w", topic, lookupErr)
+			continue
+		}
+		if len(partitions) > 0 {
+			return partitions, nil
+		}
+		err = fmt.Errorf("topic %q has no partitions", topic)
+	}
+	return nil, err
+}
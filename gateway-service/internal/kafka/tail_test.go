@@ -0,0 +1,24 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestTailReaderReportsLookupFailureAndCloses(t *testing.T) {
+	r := newTailReader(kafka.ReaderConfig{Brokers: []string{"127.0.0.1:1"}, Topic: "messages"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := r.ReadMessage(ctx); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the lookup error", err)
+	}
+	r.Close()
+	if _, err := r.ReadMessage(ctx); !errors.Is(err, io.EOF) {
+		t.Fatalf("after close got %v, want %v", err, io.EOF)
+	}
+}
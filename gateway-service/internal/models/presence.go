@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+const (
+	StatusOnline  = "online"
+	StatusAway    = "away"
+	StatusOffline = "offline"
+)
+
+type Presence struct {
+	UID      int64     `json:"UID"`
+	Status   string    `json:"Status"`
+	LastSeen time.Time `json:"LastSeen"`
+}
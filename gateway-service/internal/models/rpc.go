@@ -0,0 +1,147 @@
+package models
+
+import "time"
+
+type RoomIDResponse struct {
+	WSResponse
+	RoomID int64 `json:"RoomID"`
+	UID    int64 `json:"UID,omitempty"`
+}
+
+type RoomResponse struct {
+	WSResponse
+	RoomID     int64     `json:"RoomID"`
+	Name       string    `json:"Name"`
+	CreatorUID int64     `json:"CreatorUID"`
+	IsPrivate  bool      `json:"IsPrivate"`
+	IsDirect   bool      `json:"IsDirect"`
+	CreatedAt  time.Time `json:"CreatedAt"`
+}
+
+// DirectRoom is a direct room of the user together with the peer's profile.
+type DirectRoom struct {
+	RoomID    int64      `json:"RoomID"`
+	UID       int64      `json:"UID"`
+	Username  string     `json:"Username,omitempty"`
+	Email     string     `json:"Email,omitempty"`
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+}
+
+type DirectResponse struct {
+	WSResponse
+	RoomID  int64 `json:"RoomID"`
+	UID     int64 `json:"UID"`
+	Created bool  `json:"Created"`
+}
+
+type UserRoomsResponse struct {
+	WSResponse
+	RoomIDs []int64         `json:"RoomIDs"`
+	Directs []*DirectRoom   `json:"Directs"`
+	Unread  map[int64]int64 `json:"Unread"`
+}
+
+type HistoryResponse struct {
+	WSResponse
+	RoomID   int64      `json:"RoomID"`
+	Messages []*Message `json:"Messages"`
+}
+
+type ThreadResponse struct {
+	WSResponse
+	RoomID    int64      `json:"RoomID"`
+	MessageID int64      `json:"MessageID"`
+	Messages  []*Message `json:"Messages"`
+}
+
+type ProfileResponse struct {
+	WSResponse
+	UID       int64     `json:"UID"`
+	Username  string    `json:"Username"`
+	Email     string    `json:"Email"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+type NameResponse struct {
+	WSResponse
+	Name string `json:"Name"`
+}
+
+func NewRoomIDResponse(typ string, roomID, uid int64) *RoomIDResponse {
+	return &RoomIDResponse{
+		WSResponse: WSResponse{Type: typ},
+		RoomID:     roomID,
+		UID:        uid,
+	}
+}
+
+func NewDirectResponse(roomID, uid int64, created bool) *DirectResponse {
+	return &DirectResponse{
+		WSResponse: WSResponse{Type: "direct"},
+		RoomID:     roomID,
+		UID:        uid,
+		Created:    created,
+	}
+}
+
+// NewUserRoomsResponse lists direct rooms in Directs only, while unread
+// counts cover every room.
+func NewUserRoomsResponse(roomIDs []int64, directs []*DirectRoom, unread map[int64]int64) *UserRoomsResponse {
+	if directs == nil {
+		directs = []*DirectRoom{}
+	}
+	if unread == nil {
+		unread = make(map[int64]int64)
+	}
+	return &UserRoomsResponse{
+		WSResponse: WSResponse{Type: "rooms"},
+		RoomIDs:    WithoutDirects(roomIDs, directs),
+		Directs:    directs,
+		Unread:     unread,
+	}
+}
+
+// WithoutDirects returns the room IDs that are not direct rooms.
+func WithoutDirects(roomIDs []int64, directs []*DirectRoom) []int64 {
+	isDirect := make(map[int64]bool, len(directs))
+	for _, d := range directs {
+		isDirect[d.RoomID] = true
+	}
+	rooms := make([]int64, 0, len(roomIDs))
+	for _, id := range roomIDs {
+		if !isDirect[id] {
+			rooms = append(rooms, id)
+		}
+	}
+	return rooms
+}
+
+func NewHistoryResponse(roomID int64, messages []*Message) *HistoryResponse {
+	if messages == nil {
+		messages = []*Message{}
+	}
+	return &HistoryResponse{
+		WSResponse: WSResponse{Type: "history"},
+		RoomID:     roomID,
+		Messages:   messages,
+	}
+}
+
+func NewThreadResponse(roomID, root int64, messages []*Message) *ThreadResponse {
+	if messages == nil {
+		messages = []*Message{}
+	}
+	return &ThreadResponse{
+		WSResponse: WSResponse{Type: "thread"},
+		RoomID:     roomID,
+		MessageID:  root,
+		Messages:   messages,
+	}
+}
+
+func NewNameResponse(name string) *NameResponse {
+	return &NameResponse{
+		WSResponse: WSResponse{Type: "change_name"},
+		Name:       name,
+	}
+}
@@ -2,51 +2,425 @@ package models
 
 import "time"
 
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeInvalidOperation   = "invalid_operation"
+	CodeInvalidRoomID      = "invalid_room_id"
+	CodeInvalidStatus      = "invalid_status"
+	CodeNotInRoom          = "not_in_room"
+	CodeNotMember          = "not_member"
+	CodeNotFound           = "not_found"
+	CodeAlreadyExists      = "already_exists"
+	CodeForbidden          = "forbidden"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeUnauthenticated    = "unauthenticated"
+	CodeRateLimited        = "rate_limited"
+	CodeUnavailable        = "unavailable"
+	CodeTimeout            = "timeout"
+	CodeInternal           = "internal"
+)
+
 type WSResponse struct {
-	Type string `json:"Type"`
+	Type      string `json:"Type"`
+	RequestID string `json:"RequestID,omitempty"`
+}
+
+func (r *WSResponse) SetRequestID(id string) {
+	r.RequestID = id
 }
 
 type WSRequest struct {
-	Type      string `json:"Type"`
-	Text      string `json:"Text"`
-	NewRoomID int64  `json:"RoomID"`
+	Type      string          `json:"Type"`
+	RequestID string          `json:"RequestID"`
+	Text      string          `json:"Text"`
+	RoomID    int64           `json:"RoomID"`
+	RoomIDs   []int64         `json:"RoomIDs"`
+	LastSeen  map[int64]int64 `json:"LastSeen"`
+	Status    string          `json:"Status"`
+	MessageID int64           `json:"MessageID"`
+	ClientID  string          `json:"ClientID"`
+	Token     string          `json:"Token"`
+	Name      string          `json:"Name"`
+	IsPrivate bool            `json:"IsPrivate"`
+	UID       int64           `json:"UID"`
+	LastID    int64           `json:"LastID"`
+	Emoji     string          `json:"Emoji"`
+	ReplyTo   int64           `json:"ReplyTo"`
+}
+
+func (r *WSRequest) Rooms() []int64 {
+	if r.RoomID == 0 {
+		return r.RoomIDs
+	}
+	return append([]int64{r.RoomID}, r.RoomIDs...)
 }
 
 type Message struct {
 	WSResponse
-	ID        int64     `json:"ID"`
-	RoomID    int64     `json:"RoomID"`
-	UID       int64     `json:"UID"`
-	Text      string    `json:"Text"`
-	Timestamp time.Time `json:"Timestamp"`
+	ID            int64            `json:"ID"`
+	RoomID        int64            `json:"RoomID"`
+	UID           int64            `json:"UID"`
+	Text          string           `json:"Text"`
+	Timestamp     time.Time        `json:"Timestamp"`
+	LastReadID    int64            `json:"LastReadID,omitempty"`
+	ClientID      string           `json:"ClientID,omitempty"`
+	EditedAt      *time.Time       `json:"EditedAt,omitempty"`
+	DeletedAt     *time.Time       `json:"DeletedAt,omitempty"`
+	DeletedBy     int64            `json:"DeletedBy,omitempty"`
+	Emoji         string           `json:"Emoji,omitempty"`
+	ReactionCount *int64           `json:"ReactionCount,omitempty"`
+	Reactions     map[string]int64 `json:"Reactions,omitempty"`
+	ReplyTo       int64            `json:"ReplyTo,omitempty"`
+	ThreadRoot    int64            `json:"ThreadRoot,omitempty"`
+	ReplyCount    int64            `json:"ReplyCount,omitempty"`
+	LastReplyAt   *time.Time       `json:"LastReplyAt,omitempty"`
+}
+
+// Stored reports whether msg is a new row of the messages table. Read
+// receipts and revisions share the topic to keep their order within the
+// room but are not new rows.
+func (m *Message) Stored() bool {
+	return m.Type != "read" && !m.Revision()
+}
+
+// Revision reports whether msg changes a message that was sent before.
+func (m *Message) Revision() bool {
+	switch m.Type {
+	case "message_edited", "message_deleted", "reaction_added", "reaction_removed":
+		return true
+	}
+	return false
+}
+
+// Outgoing returns what clients receive for msg: read receipts and revisions
+// have frames of their own, stored messages go out as they are.
+func (m *Message) Outgoing() any {
+	switch {
+	case m.Type == "read":
+		return &ReadReceipt{
+			WSResponse: m.WSResponse,
+			RoomID:     m.RoomID,
+			UID:        m.UID,
+			LastReadID: m.LastReadID,
+			Timestamp:  m.Timestamp,
+		}
+	case m.Revision():
+		return &MessageRevision{
+			WSResponse:    m.WSResponse,
+			ID:            m.ID,
+			RoomID:        m.RoomID,
+			UID:           m.UID,
+			Text:          m.Text,
+			Timestamp:     m.Timestamp,
+			EditedAt:      m.EditedAt,
+			DeletedAt:     m.DeletedAt,
+			DeletedBy:     m.DeletedBy,
+			Emoji:         m.Emoji,
+			ReactionCount: m.ReactionCount,
+		}
+	}
+	return m
+}
+
+// ReadReceipt tells room members how far UID has read.
+type ReadReceipt struct {
+	WSResponse
+	RoomID     int64     `json:"RoomID"`
+	UID        int64     `json:"UID"`
+	LastReadID int64     `json:"LastReadID"`
+	Timestamp  time.Time `json:"Timestamp"`
+}
+
+// MessageRevision is an edit, a deletion or a reaction change of the message
+// with ID. Only the fields of its kind are set.
+type MessageRevision struct {
+	WSResponse
+	ID            int64      `json:"ID"`
+	RoomID        int64      `json:"RoomID"`
+	UID           int64      `json:"UID"`
+	Text          string     `json:"Text,omitempty"`
+	Timestamp     time.Time  `json:"Timestamp"`
+	EditedAt      *time.Time `json:"EditedAt,omitempty"`
+	DeletedAt     *time.Time `json:"DeletedAt,omitempty"`
+	DeletedBy     int64      `json:"DeletedBy,omitempty"`
+	Emoji         string     `json:"Emoji,omitempty"`
+	ReactionCount *int64     `json:"ReactionCount,omitempty"`
+}
+
+type Event struct {
+	WSResponse
+	RoomID   int64      `json:"RoomID,omitempty"`
+	RoomIDs  []int64    `json:"RoomIDs,omitempty"`
+	UID      int64      `json:"UID,omitempty"`
+	Status   string     `json:"Status,omitempty"`
+	LastSeen *time.Time `json:"LastSeen,omitempty"`
+}
+
+func (ev *Event) Rooms() []int64 {
+	if len(ev.RoomIDs) > 0 {
+		return ev.RoomIDs
+	}
+	return []int64{ev.RoomID}
 }
 
 type WSError struct {
 	WSResponse
+	Code  string `json:"Code"`
 	Error string `json:"Error"`
 }
 
+type RateLimitedError struct {
+	WSError
+	RetryAfter int64 `json:"RetryAfter"`
+}
+
 type SentResponse struct {
 	WSResponse
 	MessageID int64     `json:"MessageID"`
 	Timestamp time.Time `json:"Timestamp"`
+	ClientID  string    `json:"ClientID,omitempty"`
+}
+
+type EditResponse struct {
+	WSResponse
+	MessageID int64     `json:"MessageID"`
+	RoomID    int64     `json:"RoomID"`
+	EditedAt  time.Time `json:"EditedAt"`
+}
+
+type DeleteResponse struct {
+	WSResponse
+	MessageID int64     `json:"MessageID"`
+	RoomID    int64     `json:"RoomID"`
+	DeletedAt time.Time `json:"DeletedAt"`
+}
+
+type ReactionResponse struct {
+	WSResponse
+	MessageID     int64  `json:"MessageID"`
+	RoomID        int64  `json:"RoomID"`
+	Emoji         string `json:"Emoji"`
+	ReactionCount int64  `json:"ReactionCount"`
+}
+
+type ReadResponse struct {
+	WSResponse
+	RoomID     int64 `json:"RoomID"`
+	MessageID  int64 `json:"MessageID"`
+	LastReadID int64 `json:"LastReadID"`
+}
+
+type StatusResponse struct {
+	WSResponse
+	Status string `json:"Status"`
+}
+
+type QueuedResponse struct {
+	WSResponse
+	RoomID   int64  `json:"RoomID"`
+	ClientID string `json:"ClientID,omitempty"`
+}
+
+type FailedResponse struct {
+	WSError
+	RoomID   int64  `json:"RoomID"`
+	ClientID string `json:"ClientID,omitempty"`
+}
+
+type BackfillResponse struct {
+	WSResponse
+	RoomID    int64 `json:"RoomID"`
+	Count     int   `json:"Count"`
+	Truncated bool  `json:"Truncated"`
+}
+
+type AuthResponse struct {
+	WSResponse
+	ExpiresAt time.Time `json:"ExpiresAt"`
 }
 
-func NewWSError(msg string) *WSError {
+type GoingAwayEvent struct {
+	WSResponse
+	ReconnectIn int64 `json:"ReconnectIn"`
+}
+
+type RoomsResponse struct {
+	WSResponse
+	RoomIDs []int64 `json:"RoomIDs"`
+}
+
+func NewWSError(code, msg string) *WSError {
 	return &WSError{
 		WSResponse: WSResponse{Type: "error"},
+		Code:       code,
 		Error:      msg,
 	}
 }
 
-func NewSentResponse(id int64, ts time.Time) *SentResponse {
+func NewRateLimitedError(retryAfter time.Duration) *RateLimitedError {
+	return &RateLimitedError{
+		WSError:    *NewWSError(CodeRateLimited, "rate limit exceeded"),
+		RetryAfter: retryAfter.Milliseconds(),
+	}
+}
+
+func NewEditResponse(messageID, roomID int64, editedAt time.Time) *EditResponse {
+	return &EditResponse{
+		WSResponse: WSResponse{Type: "edit"},
+		MessageID:  messageID,
+		RoomID:     roomID,
+		EditedAt:   editedAt,
+	}
+}
+
+func NewDeleteResponse(messageID, roomID int64, deletedAt time.Time) *DeleteResponse {
+	return &DeleteResponse{
+		WSResponse: WSResponse{Type: "delete"},
+		MessageID:  messageID,
+		RoomID:     roomID,
+		DeletedAt:  deletedAt,
+	}
+}
+
+func NewReactionResponse(typ string, messageID, roomID int64, emoji string, count int64) *ReactionResponse {
+	return &ReactionResponse{
+		WSResponse:    WSResponse{Type: typ},
+		MessageID:     messageID,
+		RoomID:        roomID,
+		Emoji:         emoji,
+		ReactionCount: count,
+	}
+}
+
+func NewReadResponse(roomID, messageID, lastReadID int64) *ReadResponse {
+	return &ReadResponse{
+		WSResponse: WSResponse{Type: "read"},
+		RoomID:     roomID,
+		MessageID:  messageID,
+		LastReadID: lastReadID,
+	}
+}
+
+func NewStatusResponse(status string) *StatusResponse {
+	return &StatusResponse{
+		WSResponse: WSResponse{Type: "presence"},
+		Status:     status,
+	}
+}
+
+func NewQueuedResponse(roomID int64, clientID string) *QueuedResponse {
+	return &QueuedResponse{
+		WSResponse: WSResponse{Type: "queued"},
+		RoomID:     roomID,
+		ClientID:   clientID,
+	}
+}
+
+func NewFailedResponse(code, msg string, roomID int64, clientID string) *FailedResponse {
+	return &FailedResponse{
+		WSError: WSError{
+			WSResponse: WSResponse{Type: "failed"},
+			Code:       code,
+			Error:      msg,
+		},
+		RoomID:   roomID,
+		ClientID: clientID,
+	}
+}
+
+func NewSentResponse(id int64, ts time.Time, clientID string) *SentResponse {
 	return &SentResponse{
 		WSResponse: WSResponse{Type: "sent"},
 		MessageID:  id,
 		Timestamp:  ts,
+		ClientID:   clientID,
+	}
+}
+
+func NewBackfillResponse(roomID int64, count int, truncated bool) *BackfillResponse {
+	return &BackfillResponse{
+		WSResponse: WSResponse{Type: "backfill"},
+		RoomID:     roomID,
+		Count:      count,
+		Truncated:  truncated,
+	}
+}
+
+func NewAuthResponse(expiresAt time.Time) *AuthResponse {
+	return &AuthResponse{
+		WSResponse: WSResponse{Type: "auth"},
+		ExpiresAt:  expiresAt,
+	}
+}
+
+func NewAuthExpiringEvent(expiresAt time.Time) *AuthResponse {
+	return &AuthResponse{
+		WSResponse: WSResponse{Type: "auth_expiring"},
+		ExpiresAt:  expiresAt,
 	}
 }
 
-func NewEnterResponse() *WSResponse {
-	return &WSResponse{Type: "enter"}
+func NewGoingAwayEvent(reconnectIn time.Duration) *GoingAwayEvent {
+	return &GoingAwayEvent{
+		WSResponse:  WSResponse{Type: "server_going_away"},
+		ReconnectIn: reconnectIn.Milliseconds(),
+	}
+}
+
+func NewEnterResponse(roomIDs []int64) *RoomsResponse {
+	return &RoomsResponse{
+		WSResponse: WSResponse{Type: "enter"},
+		RoomIDs:    roomIDs,
+	}
+}
+
+func NewLeaveResponse(roomIDs []int64) *RoomsResponse {
+	return &RoomsResponse{
+		WSResponse: WSResponse{Type: "leave"},
+		RoomIDs:    roomIDs,
+	}
+}
+
+func NewTypingEvent(started bool, roomID, uid int64) *Event {
+	ev := &Event{
+		WSResponse: WSResponse{Type: "typing_stopped"},
+		RoomID:     roomID,
+		UID:        uid,
+	}
+	if started {
+		ev.Type = "typing_started"
+	}
+	return ev
+}
+
+// EventDisconnectUser is sent between gateway instances only: every
+// instance closes the user's connections.
+const EventDisconnectUser = "disconnect_user"
+
+// UserEvent concerns a single user rather than a room, e.g. an invite or a
+// name change, and goes to every connection of that user.
+type UserEvent struct {
+	WSResponse
+	UID       int64     `json:"UID"`
+	RoomID    int64     `json:"RoomID,omitempty"`
+	ByUID     int64     `json:"ByUID,omitempty"`
+	Name      string    `json:"Name,omitempty"`
+	Timestamp time.Time `json:"Timestamp"`
+}
+
+func NewDisconnectUserEvent(uid int64) *Event {
+	return &Event{
+		WSResponse: WSResponse{Type: EventDisconnectUser},
+		UID:        uid,
+	}
+}
+
+func NewPresenceEvent(p *Presence, roomIDs []int64) *Event {
+	return &Event{
+		WSResponse: WSResponse{Type: "presence"},
+		RoomIDs:    roomIDs,
+		UID:        p.UID,
+		Status:     p.Status,
+		LastSeen:   &p.LastSeen,
+	}
 }
@@ -0,0 +1,158 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/P3rCh1/chat-server/gateway-service/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	opConnect    = "connect"
+	opHeartbeat  = "heartbeat"
+	opStatus     = "status"
+	opDisconnect = "disconnect"
+)
+
+// update applies one connection change and returns the user's status
+// before and after it, so only real transitions are announced. Connections
+// live in a sorted set scored by their heartbeat deadline; ones whose
+// gateway died without a disconnect simply fall out once it passes.
+var update = redis.NewScript(`
+local conns, statuses, lastSeen = KEYS[1], KEYS[2], KEYS[3]
+local op, now, conn, status, ttl = ARGV[1], tonumber(ARGV[2]), ARGV[3], ARGV[4], tonumber(ARGV[5])
+
+local function aggregate()
+	for _, id in ipairs(redis.call('ZRANGEBYSCORE', conns, '-inf', now)) do
+		redis.call('HDEL', statuses, id)
+	end
+	redis.call('ZREMRANGEBYSCORE', conns, '-inf', now)
+	local alive = redis.call('ZRANGE', conns, 0, -1)
+	if #alive == 0 then
+		return 'offline'
+	end
+	for _, id in ipairs(alive) do
+		if redis.call('HGET', statuses, id) ~= 'away' then
+			return 'online'
+		end
+	end
+	return 'away'
+end
+
+local before = aggregate()
+if op == 'disconnect' then
+	redis.call('ZREM', conns, conn)
+	redis.call('HDEL', statuses, conn)
+else
+	redis.call('ZADD', conns, now + ttl, conn)
+	if op == 'heartbeat' then
+		redis.call('HSETNX', statuses, conn, 'online')
+	else
+		redis.call('HSET', statuses, conn, status)
+	end
+	redis.call('PEXPIRE', conns, ttl)
+	redis.call('PEXPIRE', statuses, ttl)
+end
+redis.call('SET', lastSeen, now)
+return {before, aggregate(), now}
+`)
+
+type Tracker struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func New(client *redis.Client, cfg *config.Presence) *Tracker {
+	return &Tracker{
+		client: client,
+		ttl:    cfg.TTL,
+	}
+}
+
+func keys(uid int64) []string {
+	prefix := fmt.Sprintf("presence:{%d}:", uid)
+	return []string{prefix + "conns", prefix + "status", prefix + "last_seen"}
+}
+
+func (t *Tracker) Connect(ctx context.Context, uid int64, connID string) (*models.Presence, bool, error) {
+	return t.update(ctx, opConnect, uid, connID, models.StatusOnline)
+}
+
+func (t *Tracker) Heartbeat(ctx context.Context, uid int64, connID string) (*models.Presence, bool, error) {
+	return t.update(ctx, opHeartbeat, uid, connID, "")
+}
+
+func (t *Tracker) SetStatus(ctx context.Context, uid int64, connID, status string) (*models.Presence, bool, error) {
+	if status != models.StatusOnline && status != models.StatusAway {
+		return nil, false, fmt.Errorf("invalid status %q", status)
+	}
+	return t.update(ctx, opStatus, uid, connID, status)
+}
+
+func (t *Tracker) Disconnect(ctx context.Context, uid int64, connID string) (*models.Presence, bool, error) {
+	return t.update(ctx, opDisconnect, uid, connID, "")
+}
+
+func (t *Tracker) update(ctx context.Context, op string, uid int64, connID, status string) (*models.Presence, bool, error) {
+	now := time.Now().UnixMilli()
+	res, err := update.Run(ctx, t.client, keys(uid), op, now, connID, status, t.ttl.Milliseconds()).Slice()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to %s presence of user id:%d: %w", op, uid, err)
+	}
+	if len(res) != 3 {
+		return nil, false, errors.New("unexpected presence script result")
+	}
+	before, _ := res[0].(string)
+	after, _ := res[1].(string)
+	seen, _ := res[2].(int64)
+	p := &models.Presence{
+		UID:      uid,
+		Status:   after,
+		LastSeen: time.UnixMilli(seen).UTC(),
+	}
+	return p, before != after, nil
+}
+
+func (t *Tracker) Get(ctx context.Context, uids ...int64) ([]*models.Presence, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	type cmds struct {
+		conns    *redis.StringSliceCmd
+		statuses *redis.MapStringStringCmd
+		lastSeen *redis.StringCmd
+	}
+	pending := make([]cmds, len(uids))
+	pipe := t.client.Pipeline()
+	for i, uid := range uids {
+		k := keys(uid)
+		pending[i] = cmds{
+			conns:    pipe.ZRangeByScore(ctx, k[0], &redis.ZRangeBy{Min: "(" + now, Max: "+inf"}),
+			statuses: pipe.HGetAll(ctx, k[1]),
+			lastSeen: pipe.Get(ctx, k[2]),
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+	result := make([]*models.Presence, len(uids))
+	for i, uid := range uids {
+		p := &models.Presence{UID: uid, Status: models.StatusOffline}
+		if seen, err := pending[i].lastSeen.Int64(); err == nil {
+			p.LastSeen = time.UnixMilli(seen).UTC()
+		}
+		statuses := pending[i].statuses.Val()
+		for _, id := range pending[i].conns.Val() {
+			if statuses[id] != models.StatusAway {
+				p.Status = models.StatusOnline
+				break
+			}
+			p.Status = models.StatusAway
+		}
+		result[i] = p
+	}
+	return result, nil
+}
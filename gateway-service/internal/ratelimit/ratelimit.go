@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/P3rCh1/chat-server/gateway-service/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// take refills every bucket for the time passed since it was last touched
+// and takes one token from each of them, but only if all of them have one.
+// Otherwise nothing is taken and the script returns how many milliseconds
+// are left until the emptiest bucket holds a token again.
+var take = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens, wait = {}, 0
+for i, key in ipairs(KEYS) do
+	local rate, burst = tonumber(ARGV[i * 2]), tonumber(ARGV[i * 2 + 1])
+	local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+	local t = tonumber(bucket[1]) or burst
+	local ts = tonumber(bucket[2]) or now
+	t = math.min(burst, t + math.max(0, now - ts) * rate)
+	if t < 1 then
+		wait = math.max(wait, math.ceil((1 - t) / rate))
+	end
+	tokens[i] = t
+end
+for i, key in ipairs(KEYS) do
+	local rate, burst = tonumber(ARGV[i * 2]), tonumber(ARGV[i * 2 + 1])
+	if wait == 0 then
+		tokens[i] = tokens[i] - 1
+	end
+	redis.call('HSET', key, 'tokens', tokens[i], 'ts', now)
+	redis.call('PEXPIRE', key, math.ceil(burst / rate))
+end
+return wait
+`)
+
+type Limiter struct {
+	client *redis.Client
+	cfg    *config.RateLimit
+}
+
+func New(client *redis.Client, cfg *config.RateLimit) *Limiter {
+	return &Limiter{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+func perMilli(rate float64) string {
+	return strconv.FormatFloat(rate/1000, 'g', -1, 64)
+}
+
+// Allow takes a token from the user's bucket, shared by all of their
+// connections on every gateway, and from the connection's own one. It
+// returns zero when the operation may go on, otherwise how long to wait.
+func (l *Limiter) Allow(ctx context.Context, uid int64, connID string) (time.Duration, error) {
+	prefix := fmt.Sprintf("ratelimit:{%d}:", uid)
+	wait, err := take.Run(
+		ctx,
+		l.client,
+		[]string{prefix + "user", prefix + "conn:" + connID},
+		time.Now().UnixMilli(),
+		perMilli(l.cfg.UserRate), l.cfg.UserBurst,
+		perMilli(l.cfg.ConnRate), l.cfg.ConnBurst,
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rate limit of user id:%d: %w", uid, err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
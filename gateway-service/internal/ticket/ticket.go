@@ -0,0 +1,58 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrUnknown = errors.New("unknown or used ticket")
+
+// Store hands out tickets that stand in for a token in the URL of /events
+// and /poll, where EventSource can't set the Authorization header. A
+// ticket lives for ttl and is gone after the first use, so a URL that ends
+// up in a log is worth nothing.
+type Store struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func New(client *redis.Client, ttl time.Duration) *Store {
+	return &Store{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func key(ticket string) string {
+	return "ticket:" + ticket
+}
+
+func (s *Store) Issue(ctx context.Context, uid int64) (string, error) {
+	ticket := uuid.New().String()
+	if err := s.client.Set(ctx, key(ticket), uid, s.ttl).Err(); err != nil {
+		return "", fmt.Errorf("failed to issue ticket for user id:%d: %w", uid, err)
+	}
+	return ticket, nil
+}
+
+// Redeem returns the user a ticket was issued to and deletes it.
+func (s *Store) Redeem(ctx context.Context, ticket string) (int64, error) {
+	raw, err := s.client.GetDel(ctx, key(ticket)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrUnknown
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to redeem ticket: %w", err)
+	}
+	uid, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redeem ticket: %w", err)
+	}
+	return uid, nil
+}
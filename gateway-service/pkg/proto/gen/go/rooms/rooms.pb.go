@@ -361,6 +361,7 @@ type GetResponse struct {
 	CreatorUID    int64                  `protobuf:"varint,3,opt,name=CreatorUID,proto3" json:"CreatorUID,omitempty"`
 	IsPrivate     bool                   `protobuf:"varint,4,opt,name=IsPrivate,proto3" json:"IsPrivate,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	IsDirect      bool                   `protobuf:"varint,6,opt,name=IsDirect,proto3" json:"IsDirect,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -430,6 +431,13 @@ func (x *GetResponse) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GetResponse) GetIsDirect() bool {
+	if x != nil {
+		return x.IsDirect
+	}
+	return false
+}
+
 type UserInRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UID           int64                  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`
@@ -614,6 +622,206 @@ func (x *IsMemberResponse) GetIsMember() bool {
 	return false
 }
 
+type DirectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UID           int64                  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`
+	PeerUID       int64                  `protobuf:"varint,2,opt,name=PeerUID,proto3" json:"PeerUID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirectRequest) Reset() {
+	*x = DirectRequest{}
+	mi := &file_rooms_rooms_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectRequest) ProtoMessage() {}
+
+func (x *DirectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rooms_rooms_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectRequest.ProtoReflect.Descriptor instead.
+func (*DirectRequest) Descriptor() ([]byte, []int) {
+	return file_rooms_rooms_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DirectRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *DirectRequest) GetPeerUID() int64 {
+	if x != nil {
+		return x.PeerUID
+	}
+	return 0
+}
+
+type DirectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=Created,proto3" json:"Created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirectResponse) Reset() {
+	*x = DirectResponse{}
+	mi := &file_rooms_rooms_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectResponse) ProtoMessage() {}
+
+func (x *DirectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rooms_rooms_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectResponse.ProtoReflect.Descriptor instead.
+func (*DirectResponse) Descriptor() ([]byte, []int) {
+	return file_rooms_rooms_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DirectResponse) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *DirectResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type Direct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	PeerUID       int64                  `protobuf:"varint,2,opt,name=PeerUID,proto3" json:"PeerUID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Direct) Reset() {
+	*x = Direct{}
+	mi := &file_rooms_rooms_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Direct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Direct) ProtoMessage() {}
+
+func (x *Direct) ProtoReflect() protoreflect.Message {
+	mi := &file_rooms_rooms_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Direct.ProtoReflect.Descriptor instead.
+func (*Direct) Descriptor() ([]byte, []int) {
+	return file_rooms_rooms_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Direct) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *Direct) GetPeerUID() int64 {
+	if x != nil {
+		return x.PeerUID
+	}
+	return 0
+}
+
+type DirectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Directs       []*Direct              `protobuf:"bytes,1,rep,name=Directs,proto3" json:"Directs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirectsResponse) Reset() {
+	*x = DirectsResponse{}
+	mi := &file_rooms_rooms_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectsResponse) ProtoMessage() {}
+
+func (x *DirectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rooms_rooms_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectsResponse.ProtoReflect.Descriptor instead.
+func (*DirectsResponse) Descriptor() ([]byte, []int) {
+	return file_rooms_rooms_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DirectsResponse) GetDirects() []*Direct {
+	if x != nil {
+		return x.Directs
+	}
+	return nil
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -622,7 +830,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_rooms_rooms_proto_msgTypes[12]
+	mi := &file_rooms_rooms_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -634,7 +842,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_rooms_rooms_proto_msgTypes[12]
+	mi := &file_rooms_rooms_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,7 +855,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_rooms_rooms_proto_rawDescGZIP(), []int{12}
+	return file_rooms_rooms_proto_rawDescGZIP(), []int{16}
 }
 
 var File_rooms_rooms_proto protoreflect.FileDescriptor
@@ -674,7 +882,7 @@ const file_rooms_rooms_proto_rawDesc = "" +
 	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\"$\n" +
 	"\n" +
 	"GetRequest\x12\x16\n" +
-	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\"\xb1\x01\n" +
+	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\"\xcd\x01\n" +
 	"\vGetResponse\x12\x16\n" +
 	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\x12\x12\n" +
 	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1e\n" +
@@ -682,7 +890,8 @@ const file_rooms_rooms_proto_rawDesc = "" +
 	"CreatorUID\x18\x03 \x01(\x03R\n" +
 	"CreatorUID\x12\x1c\n" +
 	"\tIsPrivate\x18\x04 \x01(\bR\tIsPrivate\x128\n" +
-	"\tCreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\"!\n" +
+	"\tCreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x12\x1a\n" +
+	"\bIsDirect\x18\x06 \x01(\bR\bIsDirect\"!\n" +
 	"\rUserInRequest\x12\x10\n" +
 	"\x03UID\x18\x01 \x01(\x03R\x03UID\"\"\n" +
 	"\x0eUserInResponse\x12\x10\n" +
@@ -691,15 +900,28 @@ const file_rooms_rooms_proto_rawDesc = "" +
 	"\x03UID\x18\x01 \x01(\x03R\x03UID\x12\x16\n" +
 	"\x06roomID\x18\x02 \x01(\x03R\x06roomID\".\n" +
 	"\x10IsMemberResponse\x12\x1a\n" +
-	"\bisMember\x18\x01 \x01(\bR\bisMember\"\a\n" +
-	"\x05Empty2\x88\x03\n" +
+	"\bisMember\x18\x01 \x01(\bR\bisMember\";\n" +
+	"\rDirectRequest\x12\x10\n" +
+	"\x03UID\x18\x01 \x01(\x03R\x03UID\x12\x18\n" +
+	"\aPeerUID\x18\x02 \x01(\x03R\aPeerUID\"B\n" +
+	"\x0eDirectResponse\x12\x16\n" +
+	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\x12\x18\n" +
+	"\aCreated\x18\x02 \x01(\bR\aCreated\":\n" +
+	"\x06Direct\x12\x16\n" +
+	"\x06RoomID\x18\x01 \x01(\x03R\x06RoomID\x12\x18\n" +
+	"\aPeerUID\x18\x02 \x01(\x03R\aPeerUID\"<\n" +
+	"\x0fDirectsResponse\x12)\n" +
+	"\aDirects\x18\x01 \x03(\v2\x0f.roomspb.DirectR\aDirects\"\a\n" +
+	"\x05Empty2\x8b\x04\n" +
 	"\x05rooms\x129\n" +
 	"\x06Invite\x12\x16.roomspb.InviteRequest\x1a\x17.roomspb.InviteResponse\x123\n" +
 	"\x04Join\x12\x14.roomspb.JoinRequest\x1a\x15.roomspb.JoinResponse\x129\n" +
 	"\x06Create\x12\x16.roomspb.CreateRequest\x1a\x17.roomspb.CreateResponse\x120\n" +
 	"\x03Get\x12\x13.roomspb.GetRequest\x1a\x14.roomspb.GetResponse\x129\n" +
 	"\x06UserIn\x12\x16.roomspb.UserInRequest\x1a\x17.roomspb.UserInResponse\x12?\n" +
-	"\bIsMember\x12\x18.roomspb.IsMemberRequest\x1a\x19.roomspb.IsMemberResponse\x12&\n" +
+	"\bIsMember\x12\x18.roomspb.IsMemberRequest\x1a\x19.roomspb.IsMemberResponse\x12D\n" +
+	"\x11GetOrCreateDirect\x12\x16.roomspb.DirectRequest\x1a\x17.roomspb.DirectResponse\x12;\n" +
+	"\aDirects\x12\x16.roomspb.UserInRequest\x1a\x18.roomspb.DirectsResponse\x12&\n" +
 	"\x04Ping\x12\x0e.roomspb.Empty\x1a\x0e.roomspb.EmptyB-Z+github.com/P3rCh1/chat-server/proto/roomspbb\x06proto3"
 
 var (
@@ -714,7 +936,7 @@ func file_rooms_rooms_proto_rawDescGZIP() []byte {
 	return file_rooms_rooms_proto_rawDescData
 }
 
-var file_rooms_rooms_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_rooms_rooms_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_rooms_rooms_proto_goTypes = []any{
 	(*InviteRequest)(nil),         // 0: roomspb.InviteRequest
 	(*InviteResponse)(nil),        // 1: roomspb.InviteResponse
@@ -728,30 +950,39 @@ var file_rooms_rooms_proto_goTypes = []any{
 	(*UserInResponse)(nil),        // 9: roomspb.UserInResponse
 	(*IsMemberRequest)(nil),       // 10: roomspb.IsMemberRequest
 	(*IsMemberResponse)(nil),      // 11: roomspb.IsMemberResponse
-	(*Empty)(nil),                 // 12: roomspb.Empty
-	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(*DirectRequest)(nil),         // 12: roomspb.DirectRequest
+	(*DirectResponse)(nil),        // 13: roomspb.DirectResponse
+	(*Direct)(nil),                // 14: roomspb.Direct
+	(*DirectsResponse)(nil),       // 15: roomspb.DirectsResponse
+	(*Empty)(nil),                 // 16: roomspb.Empty
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
 }
 var file_rooms_rooms_proto_depIdxs = []int32{
-	13, // 0: roomspb.GetResponse.CreatedAt:type_name -> google.protobuf.Timestamp
-	0,  // 1: roomspb.rooms.Invite:input_type -> roomspb.InviteRequest
-	2,  // 2: roomspb.rooms.Join:input_type -> roomspb.JoinRequest
-	4,  // 3: roomspb.rooms.Create:input_type -> roomspb.CreateRequest
-	6,  // 4: roomspb.rooms.Get:input_type -> roomspb.GetRequest
-	8,  // 5: roomspb.rooms.UserIn:input_type -> roomspb.UserInRequest
-	10, // 6: roomspb.rooms.IsMember:input_type -> roomspb.IsMemberRequest
-	12, // 7: roomspb.rooms.Ping:input_type -> roomspb.Empty
-	1,  // 8: roomspb.rooms.Invite:output_type -> roomspb.InviteResponse
-	3,  // 9: roomspb.rooms.Join:output_type -> roomspb.JoinResponse
-	5,  // 10: roomspb.rooms.Create:output_type -> roomspb.CreateResponse
-	7,  // 11: roomspb.rooms.Get:output_type -> roomspb.GetResponse
-	9,  // 12: roomspb.rooms.UserIn:output_type -> roomspb.UserInResponse
-	11, // 13: roomspb.rooms.IsMember:output_type -> roomspb.IsMemberResponse
-	12, // 14: roomspb.rooms.Ping:output_type -> roomspb.Empty
-	8,  // [8:15] is the sub-list for method output_type
-	1,  // [1:8] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	17, // 0: roomspb.GetResponse.CreatedAt:type_name -> google.protobuf.Timestamp
+	14, // 1: roomspb.DirectsResponse.Directs:type_name -> roomspb.Direct
+	0,  // 2: roomspb.rooms.Invite:input_type -> roomspb.InviteRequest
+	2,  // 3: roomspb.rooms.Join:input_type -> roomspb.JoinRequest
+	4,  // 4: roomspb.rooms.Create:input_type -> roomspb.CreateRequest
+	6,  // 5: roomspb.rooms.Get:input_type -> roomspb.GetRequest
+	8,  // 6: roomspb.rooms.UserIn:input_type -> roomspb.UserInRequest
+	10, // 7: roomspb.rooms.IsMember:input_type -> roomspb.IsMemberRequest
+	12, // 8: roomspb.rooms.GetOrCreateDirect:input_type -> roomspb.DirectRequest
+	8,  // 9: roomspb.rooms.Directs:input_type -> roomspb.UserInRequest
+	16, // 10: roomspb.rooms.Ping:input_type -> roomspb.Empty
+	1,  // 11: roomspb.rooms.Invite:output_type -> roomspb.InviteResponse
+	3,  // 12: roomspb.rooms.Join:output_type -> roomspb.JoinResponse
+	5,  // 13: roomspb.rooms.Create:output_type -> roomspb.CreateResponse
+	7,  // 14: roomspb.rooms.Get:output_type -> roomspb.GetResponse
+	9,  // 15: roomspb.rooms.UserIn:output_type -> roomspb.UserInResponse
+	11, // 16: roomspb.rooms.IsMember:output_type -> roomspb.IsMemberResponse
+	13, // 17: roomspb.rooms.GetOrCreateDirect:output_type -> roomspb.DirectResponse
+	15, // 18: roomspb.rooms.Directs:output_type -> roomspb.DirectsResponse
+	16, // 19: roomspb.rooms.Ping:output_type -> roomspb.Empty
+	11, // [11:20] is the sub-list for method output_type
+	2,  // [2:11] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_rooms_rooms_proto_init() }
@@ -765,7 +996,7 @@ func file_rooms_rooms_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rooms_rooms_proto_rawDesc), len(file_rooms_rooms_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
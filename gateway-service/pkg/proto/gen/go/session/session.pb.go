@@ -9,6 +9,7 @@ package sessionpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -68,6 +69,7 @@ func (x *VerifyRequest) GetToken() string {
 type VerifyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UID           int64                  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,6 +111,13 @@ func (x *VerifyResponse) GetUID() int64 {
 	return 0
 }
 
+func (x *VerifyResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 type GenerateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UID           int64                  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`
@@ -237,11 +246,12 @@ var File_session_session_proto protoreflect.FileDescriptor
 
 const file_session_session_proto_rawDesc = "" +
 	"\n" +
-	"\x15session/session.proto\x12\bsesionpb\"%\n" +
+	"\x15session/session.proto\x12\bsesionpb\x1a\x1fgoogle/protobuf/timestamp.proto\"%\n" +
 	"\rVerifyRequest\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"\"\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\\\n" +
 	"\x0eVerifyResponse\x12\x10\n" +
-	"\x03UID\x18\x01 \x01(\x03R\x03UID\"#\n" +
+	"\x03UID\x18\x01 \x01(\x03R\x03UID\x128\n" +
+	"\texpiresAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"#\n" +
 	"\x0fGenerateRequest\x12\x10\n" +
 	"\x03UID\x18\x01 \x01(\x03R\x03UID\"(\n" +
 	"\x10GenerateResponse\x12\x14\n" +
@@ -266,24 +276,26 @@ func file_session_session_proto_rawDescGZIP() []byte {
 
 var file_session_session_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_session_session_proto_goTypes = []any{
-	(*VerifyRequest)(nil),    // 0: sesionpb.VerifyRequest
-	(*VerifyResponse)(nil),   // 1: sesionpb.VerifyResponse
-	(*GenerateRequest)(nil),  // 2: sesionpb.GenerateRequest
-	(*GenerateResponse)(nil), // 3: sesionpb.GenerateResponse
-	(*Empty)(nil),            // 4: sesionpb.Empty
+	(*VerifyRequest)(nil),         // 0: sesionpb.VerifyRequest
+	(*VerifyResponse)(nil),        // 1: sesionpb.VerifyResponse
+	(*GenerateRequest)(nil),       // 2: sesionpb.GenerateRequest
+	(*GenerateResponse)(nil),      // 3: sesionpb.GenerateResponse
+	(*Empty)(nil),                 // 4: sesionpb.Empty
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
 }
 var file_session_session_proto_depIdxs = []int32{
-	0, // 0: sesionpb.Session.Verify:input_type -> sesionpb.VerifyRequest
-	2, // 1: sesionpb.Session.Generate:input_type -> sesionpb.GenerateRequest
-	4, // 2: sesionpb.Session.Ping:input_type -> sesionpb.Empty
-	1, // 3: sesionpb.Session.Verify:output_type -> sesionpb.VerifyResponse
-	3, // 4: sesionpb.Session.Generate:output_type -> sesionpb.GenerateResponse
-	4, // 5: sesionpb.Session.Ping:output_type -> sesionpb.Empty
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: sesionpb.VerifyResponse.expiresAt:type_name -> google.protobuf.Timestamp
+	0, // 1: sesionpb.Session.Verify:input_type -> sesionpb.VerifyRequest
+	2, // 2: sesionpb.Session.Generate:input_type -> sesionpb.GenerateRequest
+	4, // 3: sesionpb.Session.Ping:input_type -> sesionpb.Empty
+	1, // 4: sesionpb.Session.Verify:output_type -> sesionpb.VerifyResponse
+	3, // 5: sesionpb.Session.Generate:output_type -> sesionpb.GenerateResponse
+	4, // 6: sesionpb.Session.Ping:output_type -> sesionpb.Empty
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_session_session_proto_init() }
@@ -0,0 +1,713 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: wsframe/wsframe.proto
+
+package wspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	RequestID     string                 `protobuf:"bytes,2,opt,name=RequestID,proto3" json:"RequestID,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=Text,proto3" json:"Text,omitempty"`
+	RoomID        int64                  `protobuf:"varint,4,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	RoomIDs       []int64                `protobuf:"varint,5,rep,packed,name=RoomIDs,proto3" json:"RoomIDs,omitempty"`
+	LastSeen      map[int64]int64        `protobuf:"bytes,6,rep,name=LastSeen,proto3" json:"LastSeen,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Status        string                 `protobuf:"bytes,7,opt,name=Status,proto3" json:"Status,omitempty"`
+	MessageID     int64                  `protobuf:"varint,8,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
+	ClientID      string                 `protobuf:"bytes,9,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	Token         string                 `protobuf:"bytes,10,opt,name=Token,proto3" json:"Token,omitempty"`
+	Name          string                 `protobuf:"bytes,11,opt,name=Name,proto3" json:"Name,omitempty"`
+	IsPrivate     bool                   `protobuf:"varint,12,opt,name=IsPrivate,proto3" json:"IsPrivate,omitempty"`
+	UID           int64                  `protobuf:"varint,13,opt,name=UID,proto3" json:"UID,omitempty"`
+	LastID        int64                  `protobuf:"varint,14,opt,name=LastID,proto3" json:"LastID,omitempty"`
+	Emoji         string                 `protobuf:"bytes,15,opt,name=Emoji,proto3" json:"Emoji,omitempty"`
+	ReplyTo       int64                  `protobuf:"varint,16,opt,name=ReplyTo,proto3" json:"ReplyTo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	mi := &file_wsframe_wsframe_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_wsframe_wsframe_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_wsframe_wsframe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Request) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Request) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *Request) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Request) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *Request) GetRoomIDs() []int64 {
+	if x != nil {
+		return x.RoomIDs
+	}
+	return nil
+}
+
+func (x *Request) GetLastSeen() map[int64]int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Request) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Request) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *Request) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *Request) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Request) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Request) GetIsPrivate() bool {
+	if x != nil {
+		return x.IsPrivate
+	}
+	return false
+}
+
+func (x *Request) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *Request) GetLastID() int64 {
+	if x != nil {
+		return x.LastID
+	}
+	return 0
+}
+
+func (x *Request) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *Request) GetReplyTo() int64 {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return 0
+}
+
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	RequestID     string                 `protobuf:"bytes,2,opt,name=RequestID,proto3" json:"RequestID,omitempty"`
+	ID            int64                  `protobuf:"varint,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	RoomID        int64                  `protobuf:"varint,4,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	RoomIDs       []int64                `protobuf:"varint,5,rep,packed,name=RoomIDs,proto3" json:"RoomIDs,omitempty"`
+	UID           int64                  `protobuf:"varint,6,opt,name=UID,proto3" json:"UID,omitempty"`
+	Text          string                 `protobuf:"bytes,7,opt,name=Text,proto3" json:"Text,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	LastReadID    int64                  `protobuf:"varint,9,opt,name=LastReadID,proto3" json:"LastReadID,omitempty"`
+	ClientID      string                 `protobuf:"bytes,10,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=Status,proto3" json:"Status,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
+	Code          string                 `protobuf:"bytes,13,opt,name=Code,proto3" json:"Code,omitempty"`
+	Error         string                 `protobuf:"bytes,14,opt,name=Error,proto3" json:"Error,omitempty"`
+	MessageID     int64                  `protobuf:"varint,15,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
+	Count         int32                  `protobuf:"varint,16,opt,name=Count,proto3" json:"Count,omitempty"`
+	Truncated     bool                   `protobuf:"varint,17,opt,name=Truncated,proto3" json:"Truncated,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	RetryAfter    int64                  `protobuf:"varint,19,opt,name=RetryAfter,proto3" json:"RetryAfter,omitempty"`
+	Name          string                 `protobuf:"bytes,20,opt,name=Name,proto3" json:"Name,omitempty"`
+	IsPrivate     bool                   `protobuf:"varint,21,opt,name=IsPrivate,proto3" json:"IsPrivate,omitempty"`
+	CreatorUID    int64                  `protobuf:"varint,22,opt,name=CreatorUID,proto3" json:"CreatorUID,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	Username      string                 `protobuf:"bytes,24,opt,name=Username,proto3" json:"Username,omitempty"`
+	Email         string                 `protobuf:"bytes,25,opt,name=Email,proto3" json:"Email,omitempty"`
+	Unread        map[int64]int64        `protobuf:"bytes,26,rep,name=Unread,proto3" json:"Unread,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Messages      []*Frame               `protobuf:"bytes,27,rep,name=Messages,proto3" json:"Messages,omitempty"`
+	ReconnectIn   int64                  `protobuf:"varint,28,opt,name=ReconnectIn,proto3" json:"ReconnectIn,omitempty"`
+	ByUID         int64                  `protobuf:"varint,29,opt,name=ByUID,proto3" json:"ByUID,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,30,opt,name=EditedAt,proto3" json:"EditedAt,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,31,opt,name=DeletedAt,proto3" json:"DeletedAt,omitempty"`
+	DeletedBy     int64                  `protobuf:"varint,32,opt,name=DeletedBy,proto3" json:"DeletedBy,omitempty"`
+	Emoji         string                 `protobuf:"bytes,33,opt,name=Emoji,proto3" json:"Emoji,omitempty"`
+	ReactionCount *int64                 `protobuf:"varint,34,opt,name=ReactionCount,proto3,oneof" json:"ReactionCount,omitempty"`
+	Reactions     map[string]int64       `protobuf:"bytes,35,rep,name=Reactions,proto3" json:"Reactions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ReplyTo       int64                  `protobuf:"varint,36,opt,name=ReplyTo,proto3" json:"ReplyTo,omitempty"`
+	ThreadRoot    int64                  `protobuf:"varint,37,opt,name=ThreadRoot,proto3" json:"ThreadRoot,omitempty"`
+	ReplyCount    int64                  `protobuf:"varint,38,opt,name=ReplyCount,proto3" json:"ReplyCount,omitempty"`
+	LastReplyAt   *timestamppb.Timestamp `protobuf:"bytes,39,opt,name=LastReplyAt,proto3" json:"LastReplyAt,omitempty"`
+	Directs       []*Frame               `protobuf:"bytes,40,rep,name=Directs,proto3" json:"Directs,omitempty"`
+	IsDirect      bool                   `protobuf:"varint,41,opt,name=IsDirect,proto3" json:"IsDirect,omitempty"`
+	Created       bool                   `protobuf:"varint,42,opt,name=Created,proto3" json:"Created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_wsframe_wsframe_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_wsframe_wsframe_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_wsframe_wsframe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Frame) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Frame) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *Frame) GetID() int64 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Frame) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *Frame) GetRoomIDs() []int64 {
+	if x != nil {
+		return x.RoomIDs
+	}
+	return nil
+}
+
+func (x *Frame) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *Frame) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Frame) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Frame) GetLastReadID() int64 {
+	if x != nil {
+		return x.LastReadID
+	}
+	return 0
+}
+
+func (x *Frame) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *Frame) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Frame) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Frame) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Frame) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Frame) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *Frame) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Frame) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *Frame) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Frame) GetRetryAfter() int64 {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return 0
+}
+
+func (x *Frame) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Frame) GetIsPrivate() bool {
+	if x != nil {
+		return x.IsPrivate
+	}
+	return false
+}
+
+func (x *Frame) GetCreatorUID() int64 {
+	if x != nil {
+		return x.CreatorUID
+	}
+	return 0
+}
+
+func (x *Frame) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Frame) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Frame) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Frame) GetUnread() map[int64]int64 {
+	if x != nil {
+		return x.Unread
+	}
+	return nil
+}
+
+func (x *Frame) GetMessages() []*Frame {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *Frame) GetReconnectIn() int64 {
+	if x != nil {
+		return x.ReconnectIn
+	}
+	return 0
+}
+
+func (x *Frame) GetByUID() int64 {
+	if x != nil {
+		return x.ByUID
+	}
+	return 0
+}
+
+func (x *Frame) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *Frame) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Frame) GetDeletedBy() int64 {
+	if x != nil {
+		return x.DeletedBy
+	}
+	return 0
+}
+
+func (x *Frame) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *Frame) GetReactionCount() int64 {
+	if x != nil && x.ReactionCount != nil {
+		return *x.ReactionCount
+	}
+	return 0
+}
+
+func (x *Frame) GetReactions() map[string]int64 {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *Frame) GetReplyTo() int64 {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return 0
+}
+
+func (x *Frame) GetThreadRoot() int64 {
+	if x != nil {
+		return x.ThreadRoot
+	}
+	return 0
+}
+
+func (x *Frame) GetReplyCount() int64 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Frame) GetLastReplyAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReplyAt
+	}
+	return nil
+}
+
+func (x *Frame) GetDirects() []*Frame {
+	if x != nil {
+		return x.Directs
+	}
+	return nil
+}
+
+func (x *Frame) GetIsDirect() bool {
+	if x != nil {
+		return x.IsDirect
+	}
+	return false
+}
+
+func (x *Frame) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+var File_wsframe_wsframe_proto protoreflect.FileDescriptor
+
+const file_wsframe_wsframe_proto_rawDesc = "" +
+	"\n" +
+	"\x15wsframe/wsframe.proto\x12\x04wspb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xeb\x03\n" +
+	"\aRequest\x12\x12\n" +
+	"\x04Type\x18\x01 \x01(\tR\x04Type\x12\x1c\n" +
+	"\tRequestID\x18\x02 \x01(\tR\tRequestID\x12\x12\n" +
+	"\x04Text\x18\x03 \x01(\tR\x04Text\x12\x16\n" +
+	"\x06RoomID\x18\x04 \x01(\x03R\x06RoomID\x12\x18\n" +
+	"\aRoomIDs\x18\x05 \x03(\x03R\aRoomIDs\x127\n" +
+	"\bLastSeen\x18\x06 \x03(\v2\x1b.wspb.Request.LastSeenEntryR\bLastSeen\x12\x16\n" +
+	"\x06Status\x18\a \x01(\tR\x06Status\x12\x1c\n" +
+	"\tMessageID\x18\b \x01(\x03R\tMessageID\x12\x1a\n" +
+	"\bClientID\x18\t \x01(\tR\bClientID\x12\x14\n" +
+	"\x05Token\x18\n" +
+	" \x01(\tR\x05Token\x12\x12\n" +
+	"\x04Name\x18\v \x01(\tR\x04Name\x12\x1c\n" +
+	"\tIsPrivate\x18\f \x01(\bR\tIsPrivate\x12\x10\n" +
+	"\x03UID\x18\r \x01(\x03R\x03UID\x12\x16\n" +
+	"\x06LastID\x18\x0e \x01(\x03R\x06LastID\x12\x14\n" +
+	"\x05Emoji\x18\x0f \x01(\tR\x05Emoji\x12\x18\n" +
+	"\aReplyTo\x18\x10 \x01(\x03R\aReplyTo\x1a;\n" +
+	"\rLastSeenEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\x98\f\n" +
+	"\x05Frame\x12\x12\n" +
+	"\x04Type\x18\x01 \x01(\tR\x04Type\x12\x1c\n" +
+	"\tRequestID\x18\x02 \x01(\tR\tRequestID\x12\x0e\n" +
+	"\x02ID\x18\x03 \x01(\x03R\x02ID\x12\x16\n" +
+	"\x06RoomID\x18\x04 \x01(\x03R\x06RoomID\x12\x18\n" +
+	"\aRoomIDs\x18\x05 \x03(\x03R\aRoomIDs\x12\x10\n" +
+	"\x03UID\x18\x06 \x01(\x03R\x03UID\x12\x12\n" +
+	"\x04Text\x18\a \x01(\tR\x04Text\x128\n" +
+	"\tTimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tTimestamp\x12\x1e\n" +
+	"\n" +
+	"LastReadID\x18\t \x01(\x03R\n" +
+	"LastReadID\x12\x1a\n" +
+	"\bClientID\x18\n" +
+	" \x01(\tR\bClientID\x12\x16\n" +
+	"\x06Status\x18\v \x01(\tR\x06Status\x126\n" +
+	"\bLastSeen\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bLastSeen\x12\x12\n" +
+	"\x04Code\x18\r \x01(\tR\x04Code\x12\x14\n" +
+	"\x05Error\x18\x0e \x01(\tR\x05Error\x12\x1c\n" +
+	"\tMessageID\x18\x0f \x01(\x03R\tMessageID\x12\x14\n" +
+	"\x05Count\x18\x10 \x01(\x05R\x05Count\x12\x1c\n" +
+	"\tTruncated\x18\x11 \x01(\bR\tTruncated\x128\n" +
+	"\tExpiresAt\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tExpiresAt\x12\x1e\n" +
+	"\n" +
+	"RetryAfter\x18\x13 \x01(\x03R\n" +
+	"RetryAfter\x12\x12\n" +
+	"\x04Name\x18\x14 \x01(\tR\x04Name\x12\x1c\n" +
+	"\tIsPrivate\x18\x15 \x01(\bR\tIsPrivate\x12\x1e\n" +
+	"\n" +
+	"CreatorUID\x18\x16 \x01(\x03R\n" +
+	"CreatorUID\x128\n" +
+	"\tCreatedAt\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x12\x1a\n" +
+	"\bUsername\x18\x18 \x01(\tR\bUsername\x12\x14\n" +
+	"\x05Email\x18\x19 \x01(\tR\x05Email\x12/\n" +
+	"\x06Unread\x18\x1a \x03(\v2\x17.wspb.Frame.UnreadEntryR\x06Unread\x12'\n" +
+	"\bMessages\x18\x1b \x03(\v2\v.wspb.FrameR\bMessages\x12 \n" +
+	"\vReconnectIn\x18\x1c \x01(\x03R\vReconnectIn\x12\x14\n" +
+	"\x05ByUID\x18\x1d \x01(\x03R\x05ByUID\x126\n" +
+	"\bEditedAt\x18\x1e \x01(\v2\x1a.google.protobuf.TimestampR\bEditedAt\x128\n" +
+	"\tDeletedAt\x18\x1f \x01(\v2\x1a.google.protobuf.TimestampR\tDeletedAt\x12\x1c\n" +
+	"\tDeletedBy\x18  \x01(\x03R\tDeletedBy\x12\x14\n" +
+	"\x05Emoji\x18! \x01(\tR\x05Emoji\x12)\n" +
+	"\rReactionCount\x18\" \x01(\x03H\x00R\rReactionCount\x88\x01\x01\x128\n" +
+	"\tReactions\x18# \x03(\v2\x1a.wspb.Frame.ReactionsEntryR\tReactions\x12\x18\n" +
+	"\aReplyTo\x18$ \x01(\x03R\aReplyTo\x12\x1e\n" +
+	"\n" +
+	"ThreadRoot\x18% \x01(\x03R\n" +
+	"ThreadRoot\x12\x1e\n" +
+	"\n" +
+	"ReplyCount\x18& \x01(\x03R\n" +
+	"ReplyCount\x12<\n" +
+	"\vLastReplyAt\x18' \x01(\v2\x1a.google.protobuf.TimestampR\vLastReplyAt\x12%\n" +
+	"\aDirects\x18( \x03(\v2\v.wspb.FrameR\aDirects\x12\x1a\n" +
+	"\bIsDirect\x18) \x01(\bR\bIsDirect\x12\x18\n" +
+	"\aCreated\x18* \x01(\bR\aCreated\x1a9\n" +
+	"\vUnreadEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\x1a<\n" +
+	"\x0eReactionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01B\x10\n" +
+	"\x0e_ReactionCountB*Z(github.com/P3rCh1/chat-server/proto/wspbb\x06proto3"
+
+var (
+	file_wsframe_wsframe_proto_rawDescOnce sync.Once
+	file_wsframe_wsframe_proto_rawDescData []byte
+)
+
+func file_wsframe_wsframe_proto_rawDescGZIP() []byte {
+	file_wsframe_wsframe_proto_rawDescOnce.Do(func() {
+		file_wsframe_wsframe_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wsframe_wsframe_proto_rawDesc), len(file_wsframe_wsframe_proto_rawDesc)))
+	})
+	return file_wsframe_wsframe_proto_rawDescData
+}
+
+var file_wsframe_wsframe_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_wsframe_wsframe_proto_goTypes = []any{
+	(*Request)(nil),               // 0: wspb.Request
+	(*Frame)(nil),                 // 1: wspb.Frame
+	nil,                           // 2: wspb.Request.LastSeenEntry
+	nil,                           // 3: wspb.Frame.UnreadEntry
+	nil,                           // 4: wspb.Frame.ReactionsEntry
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_wsframe_wsframe_proto_depIdxs = []int32{
+	2,  // 0: wspb.Request.LastSeen:type_name -> wspb.Request.LastSeenEntry
+	5,  // 1: wspb.Frame.Timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 2: wspb.Frame.LastSeen:type_name -> google.protobuf.Timestamp
+	5,  // 3: wspb.Frame.ExpiresAt:type_name -> google.protobuf.Timestamp
+	5,  // 4: wspb.Frame.CreatedAt:type_name -> google.protobuf.Timestamp
+	3,  // 5: wspb.Frame.Unread:type_name -> wspb.Frame.UnreadEntry
+	1,  // 6: wspb.Frame.Messages:type_name -> wspb.Frame
+	5,  // 7: wspb.Frame.EditedAt:type_name -> google.protobuf.Timestamp
+	5,  // 8: wspb.Frame.DeletedAt:type_name -> google.protobuf.Timestamp
+	4,  // 9: wspb.Frame.Reactions:type_name -> wspb.Frame.ReactionsEntry
+	5,  // 10: wspb.Frame.LastReplyAt:type_name -> google.protobuf.Timestamp
+	1,  // 11: wspb.Frame.Directs:type_name -> wspb.Frame
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_wsframe_wsframe_proto_init() }
+func file_wsframe_wsframe_proto_init() {
+	if File_wsframe_wsframe_proto != nil {
+		return
+	}
+	file_wsframe_wsframe_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wsframe_wsframe_proto_rawDesc), len(file_wsframe_wsframe_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_wsframe_wsframe_proto_goTypes,
+		DependencyIndexes: file_wsframe_wsframe_proto_depIdxs,
+		MessageInfos:      file_wsframe_wsframe_proto_msgTypes,
+	}.Build()
+	File_wsframe_wsframe_proto = out.File
+	file_wsframe_wsframe_proto_goTypes = nil
+	file_wsframe_wsframe_proto_depIdxs = nil
+}
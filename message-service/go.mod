@@ -3,6 +3,7 @@ module github.com/P3rCh1/chat-server/message-service
 go 1.24.5
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/joho/godotenv v1.5.1
 	github.com/segmentio/kafka-go v0.4.48
 	gopkg.in/yaml.v3 v3.0.1
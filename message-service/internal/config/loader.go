@@ -12,6 +12,9 @@ type Config struct {
 	Port            string        `yaml:"port"`
 	LogLevel        string        `yaml:"log_level"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	DedupWindow     time.Duration `yaml:"dedup_window"`
+	EditWindow      time.Duration `yaml:"edit_window"`
+	Tombstones      *Tombstones   `yaml:"tombstones"`
 	Postgres        *Postgres     `yaml:"postgres"`
 	Kafka           *Kafka        `yaml:"kafka"`
 }
@@ -24,6 +27,11 @@ type Postgres struct {
 	Password string
 }
 
+type Tombstones struct {
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
 type Kafka struct {
 	Brokers []string `yaml:"brokers"`
 	Topic   string   `yaml:"topic"`
@@ -33,6 +41,12 @@ func (cfg *Config) Validate() error {
 	if cfg.Postgres.Password == "" {
 		return errors.New("postgres password is required")
 	}
+	if cfg.EditWindow < 0 {
+		return errors.New("edit_window must not be negative")
+	}
+	if cfg.Tombstones.Retention < 0 || cfg.Tombstones.PurgeInterval <= 0 {
+		return errors.New("tombstones retention must not be negative and purge_interval must be positive")
+	}
 	return nil
 }
 
@@ -41,6 +55,12 @@ func Default() *Config {
 		LogLevel:        "info",
 		Port:            ":50054",
 		ShutdownTimeout: 10 * time.Second,
+		DedupWindow:     24 * time.Hour,
+		EditWindow:      15 * time.Minute,
+		Tombstones: &Tombstones{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
 		Postgres: &Postgres{
 			Port: "5432",
 			Host: "postgres",
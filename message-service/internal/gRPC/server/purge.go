@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/P3rCh1/chat-server/message-service/internal/config"
+)
+
+// purgeTombstones drops the text of deleted messages once they have been
+// deleted for longer than the retention period.
+func (s *ServerAPI) purgeTombstones(cfg *config.Tombstones) {
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopPurge:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.PurgeInterval)
+			purged, err := s.psql.PurgeTombstones(ctx, time.Now().Add(-cfg.Retention))
+			cancel()
+			if err != nil {
+				s.log.Error("purge tombstones db error", "error", err)
+				continue
+			}
+			if purged > 0 {
+				s.log.Info("purged tombstones", "count", purged)
+			}
+		}
+	}
+}
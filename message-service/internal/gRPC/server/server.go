@@ -2,8 +2,11 @@ package server //TODO think about join msg loss
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
+	"unicode/utf8"
 
 	"github.com/P3rCh1/chat-server/message-service/internal/config"
 	"github.com/P3rCh1/chat-server/message-service/internal/models"
@@ -17,50 +20,105 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	MaxClientIDLen = 64
+	MaxEmojiLen    = 32
+)
+
 var ErrInternal = status.Error(codes.Internal, "internal error")
 
 type ServerAPI struct {
 	msgpb.UnimplementedMessageServiceServer
-	log      *slog.Logger
-	psql     *database.Postgres
-	producer *kafka.Producer
+	log         *slog.Logger
+	psql        Storage
+	producer    Producer
+	dedupWindow time.Duration
+	editWindow  time.Duration
+	stopPurge   chan struct{}
+}
+
+type Storage interface {
+	StoreMsgOnce(msg *models.Message, window time.Duration) (bool, error)
+	ThreadRoot(roomID, replyTo int64) (int64, error)
+	GetMsgs(roomID, lastID int64) ([]*msgpb.Message, error)
+	GetThread(roomID, root, lastID int64) ([]*msgpb.Message, error)
+	MarkRead(uid, roomID, messageID int64) (int64, bool, error)
+	Unread(uid int64, roomIDs []int64) (map[int64]int64, error)
+	EditMsg(id, uid int64, text string, window time.Duration) (*models.Message, error)
+	DeleteMsg(id, uid int64) (*models.Message, error)
+	AddReaction(messageID, uid int64, emoji string) (int64, int64, bool, error)
+	RemoveReaction(messageID, uid int64, emoji string) (int64, int64, bool, error)
+	PurgeTombstones(ctx context.Context, cutoff time.Time) (int64, error)
+	Close() error
+}
+
+type Producer interface {
+	Send(ctx context.Context, msg *models.Message) error
 }
 
 func New(gRPCServer *grpc.Server, cfg *config.Config) (*ServerAPI, error) {
 	s := &ServerAPI{
-		log: logger.New(cfg.LogLevel),
+		log:         logger.New(cfg.LogLevel),
+		dedupWindow: cfg.DedupWindow,
+		editWindow:  cfg.EditWindow,
 	}
-	var err error
-	if s.psql, err = database.New(cfg.Postgres); err != nil {
+	psql, err := database.New(cfg.Postgres)
+	if err != nil {
 		return nil, fmt.Errorf("postgres open fail %w", err)
 	}
+	s.psql = psql
 	s.producer = kafka.NewProducer(cfg.Kafka)
+	s.stopPurge = make(chan struct{})
+	go s.purgeTombstones(cfg.Tombstones)
 	msgpb.RegisterMessageServiceServer(gRPCServer, s)
 	return s, err
 }
 
 func (s *ServerAPI) Close() {
+	close(s.stopPurge)
 	s.psql.Close()
 }
 
 func (s *ServerAPI) Send(ctx context.Context, r *msgpb.SendRequest) (*msgpb.SendResponse, error) {
+	if len(r.ClientID) > MaxClientIDLen {
+		return nil, status.Error(codes.InvalidArgument, "client id too long")
+	}
 	msg := &models.Message{
-		UID:    r.UID,
-		RoomID: r.RoomID,
-		Text:   r.Text,
-		Type:   r.Type,
+		UID:      r.UID,
+		RoomID:   r.RoomID,
+		Text:     r.Text,
+		Type:     r.Type,
+		ClientID: r.ClientID,
+		ReplyTo:  r.ReplyTo,
 	}
-	if err := s.psql.StoreMsg(msg); err != nil {
+	if r.ReplyTo != 0 {
+		root, err := s.psql.ThreadRoot(r.RoomID, r.ReplyTo)
+		if err != nil {
+			if errors.Is(err, database.ErrMsgNotFound) {
+				return nil, status.Error(codes.NotFound, "reply target not found")
+			}
+			s.log.Error("thread root db error", "error", err)
+			return nil, ErrInternal
+		}
+		msg.ThreadRoot = root
+	}
+	duplicate, err := s.psql.StoreMsgOnce(msg, s.dedupWindow)
+	if err != nil {
 		s.log.Error("send msg db error", "error", err)
 		return nil, ErrInternal
 	}
-	if err := s.producer.Send(ctx, msg); err != nil {
-		s.log.Error("send msg kafka error", "error", err)
-		return nil, ErrInternal
+	// A duplicate is answered from the stored row and not published again,
+	// so room members never get the same message twice.
+	if !duplicate {
+		if err := s.producer.Send(ctx, msg); err != nil {
+			s.log.Error("send msg kafka error", "error", err)
+			return nil, ErrInternal
+		}
 	}
 	return &msgpb.SendResponse{
 		ID:        msg.ID,
 		Timestamp: timestamppb.New(msg.Timestamp),
+		Duplicate: duplicate,
 	}, nil
 }
 
@@ -73,6 +131,152 @@ func (s *ServerAPI) Get(ctx context.Context, r *msgpb.GetRequest) (*msgpb.GetRes
 	return &msgpb.GetResponse{Messages: msgs}, nil
 }
 
+func (s *ServerAPI) GetThread(ctx context.Context, r *msgpb.GetThreadRequest) (*msgpb.GetResponse, error) {
+	msgs, err := s.psql.GetThread(r.RoomID, r.ThreadRoot, r.LastID)
+	if err != nil {
+		if errors.Is(err, database.ErrMsgNotFound) {
+			return nil, status.Error(codes.NotFound, "thread not found")
+		}
+		s.log.Error("get thread db error", "error", err)
+		return nil, ErrInternal
+	}
+	return &msgpb.GetResponse{Messages: msgs}, nil
+}
+
+func (s *ServerAPI) MarkRead(ctx context.Context, r *msgpb.MarkReadRequest) (*msgpb.MarkReadResponse, error) {
+	if r.MessageID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+	lastReadID, advanced, err := s.psql.MarkRead(r.UID, r.RoomID, r.MessageID)
+	if err != nil {
+		if errors.Is(err, database.ErrMsgNotFound) {
+			return nil, status.Error(codes.NotFound, "message not found")
+		}
+		s.log.Error("mark read db error", "error", err)
+		return nil, ErrInternal
+	}
+	if advanced {
+		receipt := &models.Message{
+			UID:        r.UID,
+			RoomID:     r.RoomID,
+			Type:       "read",
+			Timestamp:  time.Now(),
+			LastReadID: lastReadID,
+		}
+		if err := s.producer.Send(ctx, receipt); err != nil {
+			s.log.Error("send read receipt kafka error", "error", err)
+		}
+	}
+	return &msgpb.MarkReadResponse{LastReadID: lastReadID}, nil
+}
+
+func (s *ServerAPI) Unread(ctx context.Context, r *msgpb.UnreadRequest) (*msgpb.UnreadResponse, error) {
+	counts, err := s.psql.Unread(r.UID, r.RoomIDs)
+	if err != nil {
+		s.log.Error("unread db error", "error", err)
+		return nil, ErrInternal
+	}
+	return &msgpb.UnreadResponse{Counts: counts}, nil
+}
+
+func (s *ServerAPI) Edit(ctx context.Context, r *msgpb.EditRequest) (*msgpb.EditResponse, error) {
+	if r.Text == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty text")
+	}
+	msg, err := s.psql.EditMsg(r.MessageID, r.UID, r.Text, s.editWindow)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrMsgNotFound):
+			return nil, status.Error(codes.NotFound, "message not found")
+		case errors.Is(err, database.ErrNoAccess):
+			return nil, status.Error(codes.PermissionDenied, "only the author can edit the message")
+		case errors.Is(err, database.ErrSystemMsg):
+			return nil, status.Error(codes.FailedPrecondition, "message can't be edited")
+		case errors.Is(err, database.ErrEditWindowEnd):
+			return nil, status.Error(codes.FailedPrecondition, "edit window has passed")
+		}
+		s.log.Error("edit msg db error", "error", err)
+		return nil, ErrInternal
+	}
+	msg.Type = "message_edited"
+	if err := s.producer.Send(ctx, msg); err != nil {
+		s.log.Error("send edit kafka error", "error", err)
+	}
+	return &msgpb.EditResponse{
+		RoomID:   msg.RoomID,
+		EditedAt: timestamppb.New(*msg.EditedAt),
+	}, nil
+}
+
+func (s *ServerAPI) Delete(ctx context.Context, r *msgpb.DeleteRequest) (*msgpb.DeleteResponse, error) {
+	msg, err := s.psql.DeleteMsg(r.MessageID, r.UID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrMsgNotFound):
+			return nil, status.Error(codes.NotFound, "message not found")
+		case errors.Is(err, database.ErrNoAccess):
+			return nil, status.Error(codes.PermissionDenied, "only the author or the room creator can delete the message")
+		case errors.Is(err, database.ErrSystemMsg):
+			return nil, status.Error(codes.FailedPrecondition, "message can't be deleted")
+		}
+		s.log.Error("delete msg db error", "error", err)
+		return nil, ErrInternal
+	}
+	msg.Type = "message_deleted"
+	if err := s.producer.Send(ctx, msg); err != nil {
+		s.log.Error("send delete kafka error", "error", err)
+	}
+	return &msgpb.DeleteResponse{
+		RoomID:    msg.RoomID,
+		DeletedAt: timestamppb.New(*msg.DeletedAt),
+	}, nil
+}
+
+func (s *ServerAPI) AddReaction(ctx context.Context, r *msgpb.ReactionRequest) (*msgpb.ReactionResponse, error) {
+	return s.react(ctx, r, "reaction_added", s.psql.AddReaction)
+}
+
+func (s *ServerAPI) RemoveReaction(ctx context.Context, r *msgpb.ReactionRequest) (*msgpb.ReactionResponse, error) {
+	return s.react(ctx, r, "reaction_removed", s.psql.RemoveReaction)
+}
+
+func (s *ServerAPI) react(
+	ctx context.Context,
+	r *msgpb.ReactionRequest,
+	eventType string,
+	apply func(messageID, uid int64, emoji string) (int64, int64, bool, error),
+) (*msgpb.ReactionResponse, error) {
+	if r.Emoji == "" || len(r.Emoji) > MaxEmojiLen || !utf8.ValidString(r.Emoji) {
+		return nil, status.Error(codes.InvalidArgument, "invalid emoji")
+	}
+	roomID, count, changed, err := apply(r.MessageID, r.UID, r.Emoji)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrMsgNotFound):
+			return nil, status.Error(codes.NotFound, "message not found")
+		case errors.Is(err, database.ErrNoAccess):
+			return nil, status.Error(codes.PermissionDenied, "not room member")
+		}
+		s.log.Error("reaction db error", "error", err)
+		return nil, ErrInternal
+	}
+	if changed {
+		ev := &models.Message{
+			ID:            r.MessageID,
+			RoomID:        roomID,
+			UID:           r.UID,
+			Type:          eventType,
+			Timestamp:     time.Now(),
+			Emoji:         r.Emoji,
+			ReactionCount: &count,
+		}
+		if err := s.producer.Send(ctx, ev); err != nil {
+			s.log.Error("send reaction kafka error", "error", err)
+		}
+	}
+	return &msgpb.ReactionResponse{RoomID: roomID, Count: count}, nil
+}
+
 func (s *ServerAPI) Ping(ctx context.Context, r *msgpb.Empty) (*msgpb.Empty, error) {
 	return &msgpb.Empty{}, nil
 }
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/chat-server/message-service/internal/config"
+	"github.com/P3rCh1/chat-server/message-service/internal/models"
+	"github.com/P3rCh1/chat-server/message-service/internal/storage/database"
+	msgpb "github.com/P3rCh1/chat-server/message-service/pkg/proto/gen/go/message"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeStorage struct {
+	Storage
+	editMsg         func(id, uid int64, text string, window time.Duration) (*models.Message, error)
+	deleteMsg       func(id, uid int64) (*models.Message, error)
+	purgeTombstones func(ctx context.Context, cutoff time.Time) (int64, error)
+	addReaction     func(messageID, uid int64, emoji string) (int64, int64, bool, error)
+	threadRoot      func(roomID, replyTo int64) (int64, error)
+	storeMsgOnce    func(msg *models.Message, window time.Duration) (bool, error)
+	getThread       func(roomID, root, lastID int64) ([]*msgpb.Message, error)
+}
+
+func (f *fakeStorage) EditMsg(id, uid int64, text string, window time.Duration) (*models.Message, error) {
+	return f.editMsg(id, uid, text, window)
+}
+
+func (f *fakeStorage) DeleteMsg(id, uid int64) (*models.Message, error) {
+	return f.deleteMsg(id, uid)
+}
+
+func (f *fakeStorage) PurgeTombstones(ctx context.Context, cutoff time.Time) (int64, error) {
+	return f.purgeTombstones(ctx, cutoff)
+}
+
+func (f *fakeStorage) AddReaction(messageID, uid int64, emoji string) (int64, int64, bool, error) {
+	return f.addReaction(messageID, uid, emoji)
+}
+
+func (f *fakeStorage) ThreadRoot(roomID, replyTo int64) (int64, error) {
+	return f.threadRoot(roomID, replyTo)
+}
+
+func (f *fakeStorage) StoreMsgOnce(msg *models.Message, window time.Duration) (bool, error) {
+	return f.storeMsgOnce(msg, window)
+}
+
+func (f *fakeStorage) GetThread(roomID, root, lastID int64) ([]*msgpb.Message, error) {
+	return f.getThread(roomID, root, lastID)
+}
+
+type published []*models.Message
+
+func (p *published) Send(ctx context.Context, msg *models.Message) error {
+	*p = append(*p, msg)
+	return nil
+}
+
+func newServer(psql Storage) (*ServerAPI, *published) {
+	events := &published{}
+	return &ServerAPI{
+		log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		psql:       psql,
+		producer:   events,
+		editWindow: 15 * time.Minute,
+	}, events
+}
+
+var storageCodes = map[error]codes.Code{
+	database.ErrMsgNotFound:   codes.NotFound,
+	database.ErrNoAccess:      codes.PermissionDenied,
+	database.ErrSystemMsg:     codes.FailedPrecondition,
+	database.ErrEditWindowEnd: codes.FailedPrecondition,
+	io.ErrUnexpectedEOF:       codes.Internal,
+}
+
+func TestEdit(t *testing.T) {
+	editedAt := time.Now()
+	s, events := newServer(&fakeStorage{
+		editMsg: func(id, uid int64, text string, window time.Duration) (*models.Message, error) {
+			if window != 15*time.Minute {
+				t.Errorf("edit window %v, want the configured one", window)
+			}
+			return &models.Message{ID: id, RoomID: 1, UID: uid, Type: "message", Text: text, EditedAt: &editedAt}, nil
+		},
+	})
+	resp, err := s.Edit(context.Background(), &msgpb.EditRequest{MessageID: 42, UID: 5, Text: "new text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RoomID != 1 || !resp.EditedAt.AsTime().Equal(editedAt) {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if len(*events) != 1 || (*events)[0].Type != "message_edited" || (*events)[0].Text != "new text" {
+		t.Fatalf("unexpected events %v", *events)
+	}
+}
+
+func TestEditFailures(t *testing.T) {
+	s, events := newServer(nil)
+	if _, err := s.Edit(context.Background(), &msgpb.EditRequest{MessageID: 42, UID: 5}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("empty text: got %v, want InvalidArgument", err)
+	}
+	for dbErr, want := range storageCodes {
+		s.psql = &fakeStorage{
+			editMsg: func(int64, int64, string, time.Duration) (*models.Message, error) {
+				return nil, dbErr
+			},
+		}
+		_, err := s.Edit(context.Background(), &msgpb.EditRequest{MessageID: 42, UID: 5, Text: "new text"})
+		if status.Code(err) != want {
+			t.Errorf("%v: got %v, want %v", dbErr, err, want)
+		}
+	}
+	if len(*events) != 0 {
+		t.Fatalf("failed edits published %v", *events)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	deletedAt := time.Now()
+	s, events := newServer(&fakeStorage{
+		deleteMsg: func(id, uid int64) (*models.Message, error) {
+			return &models.Message{ID: id, RoomID: 1, UID: 5, Type: "message", DeletedAt: &deletedAt, DeletedBy: uid}, nil
+		},
+	})
+	resp, err := s.Delete(context.Background(), &msgpb.DeleteRequest{MessageID: 42, UID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RoomID != 1 || !resp.DeletedAt.AsTime().Equal(deletedAt) {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if len(*events) != 1 || (*events)[0].Type != "message_deleted" || (*events)[0].DeletedBy != 1 {
+		t.Fatalf("unexpected events %v", *events)
+	}
+}
+
+func TestDeleteFailures(t *testing.T) {
+	s, events := newServer(nil)
+	for dbErr, want := range storageCodes {
+		if dbErr == database.ErrEditWindowEnd {
+			continue
+		}
+		s.psql = &fakeStorage{
+			deleteMsg: func(int64, int64) (*models.Message, error) {
+				return nil, dbErr
+			},
+		}
+		_, err := s.Delete(context.Background(), &msgpb.DeleteRequest{MessageID: 42, UID: 6})
+		if status.Code(err) != want {
+			t.Errorf("%v: got %v, want %v", dbErr, err, want)
+		}
+	}
+	if len(*events) != 0 {
+		t.Fatalf("failed deletes published %v", *events)
+	}
+}
+
+func TestPurgeTombstonesUsesRetention(t *testing.T) {
+	cutoffs := make(chan time.Time, 1)
+	s, _ := newServer(&fakeStorage{
+		purgeTombstones: func(ctx context.Context, cutoff time.Time) (int64, error) {
+			select {
+			case cutoffs <- cutoff:
+			default:
+			}
+			return 0, nil
+		},
+	})
+	s.stopPurge = make(chan struct{})
+	defer close(s.stopPurge)
+	go s.purgeTombstones(&config.Tombstones{Retention: time.Hour, PurgeInterval: 10 * time.Millisecond})
+
+	select {
+	case cutoff := <-cutoffs:
+		if age := time.Since(cutoff); age < time.Hour || age > time.Hour+time.Second {
+			t.Fatalf("purged messages deleted %v ago, want an hour", age)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tombstones were never purged")
+	}
+}
+
+func TestReactionEvents(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		changed bool
+		events  int
+	}{
+		{"new reaction", true, 1},
+		{"repeated reaction", false, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, events := newServer(&fakeStorage{
+				addReaction: func(int64, int64, string) (int64, int64, bool, error) {
+					return 1, 3, tc.changed, nil
+				},
+			})
+			resp, err := s.AddReaction(context.Background(), &msgpb.ReactionRequest{MessageID: 42, UID: 5, Emoji: "👍"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.RoomID != 1 || resp.Count != 3 {
+				t.Fatalf("unexpected response %v", resp)
+			}
+			if len(*events) != tc.events {
+				t.Fatalf("published %d events, want %d", len(*events), tc.events)
+			}
+			if tc.events > 0 {
+				ev := (*events)[0]
+				if ev.Type != "reaction_added" || ev.Emoji != "👍" || ev.ReactionCount == nil || *ev.ReactionCount != 3 {
+					t.Fatalf("unexpected event %+v", ev)
+				}
+			}
+		})
+	}
+}
+
+func TestReactionFailures(t *testing.T) {
+	s, _ := newServer(&fakeStorage{})
+	for _, emoji := range []string{"", strings.Repeat("a", MaxEmojiLen+1), "\xff"} {
+		_, err := s.AddReaction(context.Background(), &msgpb.ReactionRequest{MessageID: 42, UID: 5, Emoji: emoji})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%q: got %v, want InvalidArgument", emoji, err)
+		}
+	}
+	for _, dbErr := range []error{database.ErrNoAccess, database.ErrMsgNotFound, io.ErrUnexpectedEOF} {
+		s.psql = &fakeStorage{
+			addReaction: func(int64, int64, string) (int64, int64, bool, error) {
+				return 0, 0, false, dbErr
+			},
+		}
+		_, err := s.AddReaction(context.Background(), &msgpb.ReactionRequest{MessageID: 42, UID: 6, Emoji: "👍"})
+		if want := storageCodes[dbErr]; status.Code(err) != want {
+			t.Errorf("%v: got %v, want %v", dbErr, err, want)
+		}
+	}
+}
+
+func TestSendReplyJoinsThread(t *testing.T) {
+	var stored *models.Message
+	s, events := newServer(&fakeStorage{
+		threadRoot: func(roomID, replyTo int64) (int64, error) {
+			if roomID != 1 || replyTo != 43 {
+				t.Errorf("thread root of %d in room %d", replyTo, roomID)
+			}
+			return 40, nil
+		},
+		storeMsgOnce: func(msg *models.Message, window time.Duration) (bool, error) {
+			stored = msg
+			msg.ID = 44
+			msg.Timestamp = time.Now()
+			return false, nil
+		},
+	})
+	resp, err := s.Send(context.Background(), &msgpb.SendRequest{RoomID: 1, UID: 5, Type: "message", Text: "hi", ReplyTo: 43})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 44 || stored.ReplyTo != 43 || stored.ThreadRoot != 40 {
+		t.Fatalf("stored %+v, response %v", stored, resp)
+	}
+	if len(*events) != 1 || (*events)[0].ThreadRoot != 40 {
+		t.Fatalf("unexpected events %v", *events)
+	}
+}
+
+func TestThreadOfMissingMessage(t *testing.T) {
+	s, events := newServer(&fakeStorage{
+		threadRoot: func(int64, int64) (int64, error) {
+			return 0, database.ErrMsgNotFound
+		},
+		getThread: func(int64, int64, int64) ([]*msgpb.Message, error) {
+			return nil, database.ErrMsgNotFound
+		},
+	})
+	_, err := s.Send(context.Background(), &msgpb.SendRequest{RoomID: 1, UID: 5, Type: "message", Text: "hi", ReplyTo: 43})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("send: got %v, want NotFound", err)
+	}
+	if len(*events) != 0 {
+		t.Fatalf("published %v", *events)
+	}
+	_, err = s.GetThread(context.Background(), &msgpb.GetThreadRequest{RoomID: 1, ThreadRoot: 43})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("get thread: got %v, want NotFound", err)
+	}
+}
+
+func TestSendDuplicateIsNotPublished(t *testing.T) {
+	sent := time.Now().Add(-time.Minute)
+	s, events := newServer(&fakeStorage{
+		storeMsgOnce: func(msg *models.Message, window time.Duration) (bool, error) {
+			msg.ID = 42
+			msg.Timestamp = sent
+			return true, nil
+		},
+	})
+	resp, err := s.Send(context.Background(), &msgpb.SendRequest{RoomID: 1, UID: 5, Type: "message", Text: "hi", ClientID: "c1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 42 || !resp.Duplicate || !resp.Timestamp.AsTime().Equal(sent) {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if len(*events) != 0 {
+		t.Fatalf("duplicate published %v", *events)
+	}
+}
@@ -5,10 +5,19 @@ import (
 )
 
 type Message struct {
-	ID        int64     `json:"ID"`
-	RoomID    int64     `json:"RoomID"`
-	UID       int64     `json:"UID"`
-	Type      string    `json:"Type"`
-	Text      string    `json:"Text"`
-	Timestamp time.Time `json:"Timestamp"`
+	ID            int64      `json:"ID"`
+	RoomID        int64      `json:"RoomID"`
+	UID           int64      `json:"UID"`
+	Type          string     `json:"Type"`
+	Text          string     `json:"Text"`
+	Timestamp     time.Time  `json:"Timestamp"`
+	LastReadID    int64      `json:"LastReadID,omitempty"`
+	ClientID      string     `json:"ClientID,omitempty"`
+	EditedAt      *time.Time `json:"EditedAt,omitempty"`
+	DeletedAt     *time.Time `json:"DeletedAt,omitempty"`
+	DeletedBy     int64      `json:"DeletedBy,omitempty"`
+	Emoji         string     `json:"Emoji,omitempty"`
+	ReactionCount *int64     `json:"ReactionCount,omitempty"`
+	ReplyTo       int64      `json:"ReplyTo,omitempty"`
+	ThreadRoot    int64      `json:"ThreadRoot,omitempty"`
 }
@@ -3,18 +3,26 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/P3rCh1/chat-server/message-service/internal/config"
 	"github.com/P3rCh1/chat-server/message-service/internal/models"
 	msgpb "github.com/P3rCh1/chat-server/message-service/pkg/proto/gen/go/message"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const Limit = 100
 
+var (
+	ErrMsgNotFound   = errors.New("message not found in room")
+	ErrNoAccess      = errors.New("no access to the message")
+	ErrSystemMsg     = errors.New("system messages can't be changed")
+	ErrEditWindowEnd = errors.New("edit window has passed")
+)
+
 type Postgres struct {
 	db *sql.DB
 }
@@ -59,6 +67,54 @@ func migrate(ctx context.Context, db *sql.DB) error {
     		text TEXT,
 			timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
+
+		CREATE TABLE IF NOT EXISTS read_cursors (
+			user_id INTEGER REFERENCES users(id),
+			room_id INTEGER REFERENCES rooms(id),
+			last_read_id INTEGER NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, room_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS messages_room_id_id_idx ON messages (room_id, id);
+
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS client_id VARCHAR(64);
+
+		CREATE INDEX IF NOT EXISTS messages_user_id_client_id_idx
+			ON messages (user_id, client_id) WHERE client_id IS NOT NULL;
+
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+		CREATE TABLE IF NOT EXISTS message_revisions (
+			id SERIAL PRIMARY KEY,
+			message_id INTEGER REFERENCES messages(id) NOT NULL,
+			text TEXT,
+			replaced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS message_revisions_message_id_idx ON message_revisions (message_id);
+
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_by INTEGER REFERENCES users(id);
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id INTEGER REFERENCES messages(id),
+			user_id INTEGER REFERENCES users(id),
+			emoji VARCHAR(32),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (message_id, user_id, emoji)
+		);
+
+		CREATE INDEX IF NOT EXISTS messages_deleted_at_idx
+			ON messages (deleted_at) WHERE deleted_at IS NOT NULL AND text IS NOT NULL;
+
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS reply_to INTEGER REFERENCES messages(id);
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_root INTEGER REFERENCES messages(id);
+
+		CREATE INDEX IF NOT EXISTS messages_thread_root_id_idx
+			ON messages (thread_root, id) WHERE thread_root IS NOT NULL;
+
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS is_direct BOOLEAN NOT NULL DEFAULT false;
 	`
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -74,11 +130,14 @@ func (p *Postgres) StoreMsg(msg *models.Message) error {
             room_id,
             user_id,
 			type,
-            text
-        ) VALUES ($1, $2, $3, $4)
+            text,
+			client_id,
+			reply_to,
+			thread_root
+        ) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, 0), NULLIF($7, 0))
 		RETURNING id, timestamp
     `
-	row := p.db.QueryRow(query, msg.RoomID, msg.UID, msg.Type, msg.Text)
+	row := p.db.QueryRow(query, msg.RoomID, msg.UID, msg.Type, msg.Text, msg.ClientID, msg.ReplyTo, msg.ThreadRoot)
 	err := row.Scan(&msg.ID, &msg.Timestamp)
 	if err != nil {
 		return fmt.Errorf("store msg fail: %w", err)
@@ -86,40 +145,524 @@ func (p *Postgres) StoreMsg(msg *models.Message) error {
 	return nil
 }
 
+// StoreMsgOnce stores msg unless its author already sent a message with the
+// same client ID within window. Then msg is filled from the original row and
+// duplicate is true. Retries of one client ID are serialized by an
+// advisory lock, so concurrent ones can't both insert.
+func (p *Postgres) StoreMsgOnce(msg *models.Message, window time.Duration) (duplicate bool, err error) {
+	if msg.ClientID == "" {
+		return false, p.StoreMsg(msg)
+	}
+	tx, err := p.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("store msg begin tx fail: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1::INTEGER, hashtext($2))`, msg.UID, msg.ClientID); err != nil {
+		return false, fmt.Errorf("store msg lock fail: %w", err)
+	}
+	const selectQuery = `
+		SELECT id, room_id, type, COALESCE(text, ''), timestamp, COALESCE(reply_to, 0), COALESCE(thread_root, 0)
+		FROM messages
+		WHERE user_id = $1 AND client_id = $2 AND timestamp > $3
+		ORDER BY id DESC LIMIT 1
+	`
+	err = tx.QueryRow(selectQuery, msg.UID, msg.ClientID, time.Now().Add(-window)).Scan(
+		&msg.ID, &msg.RoomID, &msg.Type, &msg.Text, &msg.Timestamp, &msg.ReplyTo, &msg.ThreadRoot,
+	)
+	switch {
+	case err == nil:
+		return true, tx.Commit()
+	case !errors.Is(err, sql.ErrNoRows):
+		return false, fmt.Errorf("store msg dedupe fail: %w", err)
+	}
+	const insertQuery = `
+		INSERT INTO messages (room_id, user_id, type, text, client_id, reply_to, thread_root)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), NULLIF($7, 0))
+		RETURNING id, timestamp
+	`
+	err = tx.QueryRow(
+		insertQuery,
+		msg.RoomID, msg.UID, msg.Type, msg.Text, msg.ClientID, msg.ReplyTo, msg.ThreadRoot,
+	).Scan(&msg.ID, &msg.Timestamp)
+	if err != nil {
+		return false, fmt.Errorf("store msg fail: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("store msg commit fail: %w", err)
+	}
+	return false, nil
+}
+
+const msgColumns = `
+	id, room_id, user_id, type, COALESCE(text, ''), timestamp, COALESCE(client_id, ''),
+	edited_at, deleted_at, COALESCE(reply_to, 0), COALESCE(thread_root, 0)
+`
+
 func (p *Postgres) GetMsgs(roomID, lastID int64) ([]*msgpb.Message, error) {
 	var rows *sql.Rows
 	var err error
 	if lastID != 0 {
 		const query = `
-        SELECT id, user_id, type, text, timestamp
+        SELECT ` + msgColumns + `
 		FROM messages
 		WHERE room_id = $1 AND id <= $2
-		ORDER BY timestamp DESC LIMIT $3
+		ORDER BY id DESC LIMIT $3
     `
 		rows, err = p.db.Query(query, roomID, lastID, Limit)
 	} else {
 		const query = `
-        SELECT id, user_id, type, text, timestamp
+        SELECT ` + msgColumns + `
 		FROM messages
 		WHERE room_id = $1
-		ORDER BY timestamp DESC LIMIT $2
+		ORDER BY id DESC LIMIT $2
     `
 		rows, err = p.db.Query(query, roomID, Limit)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
+	return p.readMsgs(rows)
+}
+
+// ThreadRoot returns the root of the thread a reply to replyTo belongs to:
+// replies to a reply join the thread of the message they answer.
+func (p *Postgres) ThreadRoot(roomID, replyTo int64) (int64, error) {
+	const query = `
+		SELECT COALESCE(thread_root, id)
+		FROM messages
+		WHERE id = $1 AND room_id = $2 AND deleted_at IS NULL
+	`
+	var root int64
+	err := p.db.QueryRow(query, replyTo, roomID).Scan(&root)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrMsgNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get thread root: %w", err)
+	}
+	return root, nil
+}
+
+// GetThread pages back through the replies of a thread like GetMsgs does
+// through a room.
+func (p *Postgres) GetThread(roomID, root, lastID int64) ([]*msgpb.Message, error) {
+	const rootQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM messages WHERE id = $1 AND room_id = $2 AND thread_root IS NULL
+		)
+	`
+	var found bool
+	if err := p.db.QueryRow(rootQuery, root, roomID).Scan(&found); err != nil {
+		return nil, fmt.Errorf("failed to get thread root: %w", err)
+	}
+	if !found {
+		return nil, ErrMsgNotFound
+	}
+	const query = `
+		SELECT ` + msgColumns + `
+		FROM messages
+		WHERE thread_root = $1 AND ($2 = 0 OR id <= $2)
+		ORDER BY id DESC LIMIT $3
+	`
+	rows, err := p.db.Query(query, root, lastID, Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	return p.readMsgs(rows)
+}
+
+// readMsgs scans rows of msgColumns and adds reactions and thread stats.
+func (p *Postgres) readMsgs(rows *sql.Rows) ([]*msgpb.Message, error) {
 	defer rows.Close()
 	msgs := make([]*msgpb.Message, 0, Limit)
 	for rows.Next() {
 		msg := msgpb.Message{}
 		var timestamp time.Time
-		if err := rows.Scan(&msg.ID, &msg.UID, &msg.Type, &msg.Text, &timestamp); err != nil {
+		var editedAt, deletedAt sql.NullTime
+		err := rows.Scan(
+			&msg.ID, &msg.RoomID, &msg.UID, &msg.Type, &msg.Text, &timestamp, &msg.ClientID,
+			&editedAt, &deletedAt, &msg.ReplyTo, &msg.ThreadRoot,
+		)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan msg: %w", err)
 		}
 		msg.Timestamp = timestamppb.New(timestamp)
-		msg.RoomID = roomID
+		if editedAt.Valid {
+			msg.EditedAt = timestamppb.New(editedAt.Time)
+		}
+		if deletedAt.Valid {
+			msg.Text = ""
+			msg.DeletedAt = timestamppb.New(deletedAt.Time)
+		}
 		msgs = append(msgs, &msg)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+	if err := p.addReactions(msgs); err != nil {
+		return nil, err
+	}
+	if err := p.addThreadStats(msgs); err != nil {
+		return nil, err
+	}
 	return msgs, nil
 }
+
+// addThreadStats fills the reply count and the last reply time of thread
+// roots among msgs.
+func (p *Postgres) addThreadStats(msgs []*msgpb.Message) error {
+	byID := make(map[int64]*msgpb.Message, len(msgs))
+	ids := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.ThreadRoot == 0 {
+			byID[msg.ID] = msg
+			ids = append(ids, msg.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	const query = `
+		SELECT thread_root, COUNT(*), MAX(timestamp)
+		FROM messages
+		WHERE thread_root = ANY($1::INTEGER[]) AND deleted_at IS NULL
+		GROUP BY thread_root
+	`
+	rows, err := p.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get thread stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var lastReplyAt time.Time
+		msg := &msgpb.Message{}
+		if err := rows.Scan(&id, &msg.ReplyCount, &lastReplyAt); err != nil {
+			return fmt.Errorf("failed to scan thread stats: %w", err)
+		}
+		byID[id].ReplyCount = msg.ReplyCount
+		byID[id].LastReplyAt = timestamppb.New(lastReplyAt)
+	}
+	return rows.Err()
+}
+
+// addReactions fills the reaction counts of msgs, grouped by emoji.
+func (p *Postgres) addReactions(msgs []*msgpb.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*msgpb.Message, len(msgs))
+	ids := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.DeletedAt == nil {
+			byID[msg.ID] = msg
+			ids = append(ids, msg.ID)
+		}
+	}
+	const query = `
+		SELECT message_id, emoji, COUNT(*)
+		FROM message_reactions
+		WHERE message_id = ANY($1::INTEGER[])
+		GROUP BY message_id, emoji
+	`
+	rows, err := p.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, count int64
+		var emoji string
+		if err := rows.Scan(&id, &emoji, &count); err != nil {
+			return fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		msg := byID[id]
+		if msg.Reactions == nil {
+			msg.Reactions = make(map[string]int64)
+		}
+		msg.Reactions[emoji] = count
+	}
+	return rows.Err()
+}
+
+// EditMsg replaces the text of a message sent by uid within window (zero
+// means no limit) and keeps the previous text in message_revisions. It
+// returns the message as it is after the edit.
+func (p *Postgres) EditMsg(id, uid int64, text string, window time.Duration) (*models.Message, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("edit msg begin tx fail: %w", err)
+	}
+	defer tx.Rollback()
+	msg := &models.Message{ID: id}
+	var oldText sql.NullString
+	var deleted bool
+	const selectQuery = `
+		SELECT room_id, user_id, type, text, timestamp, deleted_at IS NOT NULL
+		FROM messages
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRow(selectQuery, id).Scan(&msg.RoomID, &msg.UID, &msg.Type, &oldText, &msg.Timestamp, &deleted)
+	switch {
+	case errors.Is(err, sql.ErrNoRows), err == nil && deleted:
+		return nil, ErrMsgNotFound
+	case err != nil:
+		return nil, fmt.Errorf("edit msg select fail: %w", err)
+	case msg.UID != uid:
+		return nil, ErrNoAccess
+	case msg.Type != "message":
+		return nil, ErrSystemMsg
+	case window > 0 && time.Since(msg.Timestamp) > window:
+		return nil, ErrEditWindowEnd
+	}
+	const revisionQuery = `
+		INSERT INTO message_revisions (message_id, text)
+		VALUES ($1, $2)
+	`
+	if _, err := tx.Exec(revisionQuery, id, oldText); err != nil {
+		return nil, fmt.Errorf("edit msg revision fail: %w", err)
+	}
+	const updateQuery = `
+		UPDATE messages
+		SET text = $2, edited_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING edited_at
+	`
+	var editedAt time.Time
+	if err := tx.QueryRow(updateQuery, id, text).Scan(&editedAt); err != nil {
+		return nil, fmt.Errorf("edit msg update fail: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("edit msg commit fail: %w", err)
+	}
+	msg.Text = text
+	msg.EditedAt = &editedAt
+	return msg, nil
+}
+
+// DeleteMsg turns a message into a tombstone: the row stays, so history
+// pages keep their IDs, but its text is no longer returned. The author may
+// delete it, and so may the room creator unless the room is a direct one.
+func (p *Postgres) DeleteMsg(id, uid int64) (*models.Message, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("delete msg begin tx fail: %w", err)
+	}
+	defer tx.Rollback()
+	msg := &models.Message{ID: id}
+	var creatorID int64
+	var deleted, direct bool
+	const selectQuery = `
+		SELECT m.room_id, m.user_id, m.type, m.timestamp, m.deleted_at IS NOT NULL, r.creator_id, r.is_direct
+		FROM messages m
+		JOIN rooms r ON r.id = m.room_id
+		WHERE m.id = $1
+		FOR UPDATE OF m
+	`
+	err = tx.QueryRow(selectQuery, id).Scan(
+		&msg.RoomID, &msg.UID, &msg.Type, &msg.Timestamp, &deleted, &creatorID, &direct,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows), err == nil && deleted:
+		return nil, ErrMsgNotFound
+	case err != nil:
+		return nil, fmt.Errorf("delete msg select fail: %w", err)
+	case msg.UID != uid && (direct || creatorID != uid):
+		return nil, ErrNoAccess
+	case msg.Type != "message":
+		return nil, ErrSystemMsg
+	}
+	const updateQuery = `
+		UPDATE messages
+		SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2
+		WHERE id = $1
+		RETURNING deleted_at
+	`
+	var deletedAt time.Time
+	if err := tx.QueryRow(updateQuery, id, uid).Scan(&deletedAt); err != nil {
+		return nil, fmt.Errorf("delete msg update fail: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("delete msg commit fail: %w", err)
+	}
+	msg.DeletedAt = &deletedAt
+	msg.DeletedBy = uid
+	return msg, nil
+}
+
+// AddReaction adds the user's emoji to a message of a room they are a member
+// of. It returns the room, how many users reacted with the emoji and whether
+// the reaction is new.
+func (p *Postgres) AddReaction(messageID, uid int64, emoji string) (roomID, count int64, added bool, err error) {
+	const query = `
+		WITH target AS (
+			SELECT m.id, m.room_id, EXISTS (
+				SELECT 1 FROM room_members rm WHERE rm.room_id = m.room_id AND rm.user_id = $2
+			) AS member
+			FROM messages m
+			WHERE m.id = $1 AND m.deleted_at IS NULL
+		), added AS (
+			INSERT INTO message_reactions (message_id, user_id, emoji)
+			SELECT id, $2, $3 FROM target WHERE member
+			ON CONFLICT DO NOTHING
+			RETURNING 1
+		)
+		SELECT
+			(SELECT room_id FROM target),
+			COALESCE((SELECT member FROM target), false),
+			EXISTS (SELECT 1 FROM added),
+			(SELECT COUNT(*) FROM message_reactions WHERE message_id = $1 AND emoji = $3)
+	`
+	return p.react(query, messageID, uid, emoji, 1)
+}
+
+// RemoveReaction takes the user's emoji off a message, like AddReaction.
+func (p *Postgres) RemoveReaction(messageID, uid int64, emoji string) (roomID, count int64, removed bool, err error) {
+	const query = `
+		WITH target AS (
+			SELECT m.id, m.room_id, EXISTS (
+				SELECT 1 FROM room_members rm WHERE rm.room_id = m.room_id AND rm.user_id = $2
+			) AS member
+			FROM messages m
+			WHERE m.id = $1 AND m.deleted_at IS NULL
+		), removed AS (
+			DELETE FROM message_reactions
+			WHERE message_id = $1 AND user_id = $2 AND emoji = $3
+				AND EXISTS (SELECT 1 FROM target WHERE member)
+			RETURNING 1
+		)
+		SELECT
+			(SELECT room_id FROM target),
+			COALESCE((SELECT member FROM target), false),
+			EXISTS (SELECT 1 FROM removed),
+			(SELECT COUNT(*) FROM message_reactions WHERE message_id = $1 AND emoji = $3)
+	`
+	return p.react(query, messageID, uid, emoji, -1)
+}
+
+// react runs a reaction query. Its count is taken before the change, as all
+// parts of one statement see the same snapshot, so delta is applied here.
+func (p *Postgres) react(query string, messageID, uid int64, emoji string, delta int64) (int64, int64, bool, error) {
+	var roomID sql.NullInt64
+	var member, changed bool
+	var count int64
+	err := p.db.QueryRow(query, messageID, uid, emoji).Scan(&roomID, &member, &changed, &count)
+	switch {
+	case err != nil:
+		return 0, 0, false, fmt.Errorf("failed to react: %w", err)
+	case !roomID.Valid:
+		return 0, 0, false, ErrMsgNotFound
+	case !member:
+		return 0, 0, false, ErrNoAccess
+	}
+	if changed {
+		count += delta
+	}
+	return roomID.Int64, count, changed, nil
+}
+
+// PurgeTombstones drops the text, the revisions and the reactions of
+// messages deleted before cutoff and reports how many messages it purged.
+func (p *Postgres) PurgeTombstones(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("purge begin tx fail: %w", err)
+	}
+	defer tx.Rollback()
+	const revisionsQuery = `
+		DELETE FROM message_revisions
+		WHERE message_id IN (
+			SELECT id FROM messages WHERE deleted_at < $1
+		)
+	`
+	if _, err := tx.ExecContext(ctx, revisionsQuery, cutoff); err != nil {
+		return 0, fmt.Errorf("purge revisions fail: %w", err)
+	}
+	const reactionsQuery = `
+		DELETE FROM message_reactions
+		WHERE message_id IN (
+			SELECT id FROM messages WHERE deleted_at < $1
+		)
+	`
+	if _, err := tx.ExecContext(ctx, reactionsQuery, cutoff); err != nil {
+		return 0, fmt.Errorf("purge reactions fail: %w", err)
+	}
+	const messagesQuery = `
+		UPDATE messages
+		SET text = NULL
+		WHERE deleted_at < $1 AND text IS NOT NULL
+	`
+	res, err := tx.ExecContext(ctx, messagesQuery, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge messages fail: %w", err)
+	}
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge rows affected fail: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("purge commit fail: %w", err)
+	}
+	return purged, nil
+}
+
+// MarkRead moves the user's cursor in the room forward to messageID and
+// reports whether it moved. The cursor never goes back.
+func (p *Postgres) MarkRead(uid, roomID, messageID int64) (lastReadID int64, advanced bool, err error) {
+	const query = `
+		WITH target AS (
+			SELECT id FROM messages WHERE id = $3 AND room_id = $2
+		), old AS (
+			SELECT last_read_id FROM read_cursors WHERE user_id = $1 AND room_id = $2
+		), upsert AS (
+			INSERT INTO read_cursors (user_id, room_id, last_read_id)
+			SELECT $1, $2, id FROM target
+			ON CONFLICT (user_id, room_id) DO UPDATE
+			SET last_read_id = EXCLUDED.last_read_id, updated_at = CURRENT_TIMESTAMP
+			WHERE read_cursors.last_read_id < EXCLUDED.last_read_id
+			RETURNING last_read_id
+		)
+		SELECT
+			EXISTS (SELECT 1 FROM target),
+			COALESCE((SELECT last_read_id FROM upsert), (SELECT last_read_id FROM old), 0),
+			EXISTS (SELECT 1 FROM upsert)
+	`
+	var found bool
+	err = p.db.QueryRow(query, uid, roomID, messageID).Scan(&found, &lastReadID, &advanced)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to mark read: %w", err)
+	}
+	if !found {
+		return 0, false, ErrMsgNotFound
+	}
+	return lastReadID, advanced, nil
+}
+
+func (p *Postgres) Unread(uid int64, roomIDs []int64) (map[int64]int64, error) {
+	const query = `
+		SELECT r.room_id, COUNT(m.id)
+		FROM unnest($2::INTEGER[]) AS r(room_id)
+		LEFT JOIN read_cursors c ON c.user_id = $1 AND c.room_id = r.room_id
+		LEFT JOIN messages m ON m.room_id = r.room_id
+			AND m.id > COALESCE(c.last_read_id, 0)
+			AND m.user_id IS DISTINCT FROM $1
+			AND m.deleted_at IS NULL
+		GROUP BY r.room_id
+	`
+	rows, err := p.db.Query(query, uid, pq.Array(roomIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread: %w", err)
+	}
+	defer rows.Close()
+	counts := make(map[int64]int64, len(roomIDs))
+	for rows.Next() {
+		var roomID, count int64
+		if err := rows.Scan(&roomID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread: %w", err)
+		}
+		counts[roomID] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,434 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newMock(t *testing.T) (*Postgres, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return &Postgres{db}, mock
+}
+
+func expectEditSelect(mock sqlmock.Sqlmock, typ string, sent time.Time, deleted bool) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT room_id, user_id, type, text, timestamp`).
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "type", "text", "timestamp", "deleted"}).
+			AddRow(1, 5, typ, "old text", sent, deleted))
+}
+
+func TestEditMsgWithinWindow(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		sent   time.Time
+		window time.Duration
+	}{
+		{"inside the window", time.Now().Add(-time.Minute), 15 * time.Minute},
+		{"no window", time.Now().Add(-24 * time.Hour), 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			editedAt := time.Now()
+			expectEditSelect(mock, "message", tc.sent, false)
+			mock.ExpectExec(`INSERT INTO message_revisions`).
+				WithArgs(42, "old text").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(`UPDATE messages\s+SET text = \$2, edited_at`).
+				WithArgs(42, "new text").
+				WillReturnRows(sqlmock.NewRows([]string{"edited_at"}).AddRow(editedAt))
+			mock.ExpectCommit()
+
+			msg, err := p.EditMsg(42, 5, "new text", tc.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.Text != "new text" || msg.RoomID != 1 || msg.EditedAt == nil || !msg.EditedAt.Equal(editedAt) {
+				t.Fatalf("unexpected message %+v", msg)
+			}
+		})
+	}
+}
+
+func TestEditMsgRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		uid     int64
+		typ     string
+		sent    time.Time
+		deleted bool
+		want    error
+	}{
+		{"after the window", 5, "message", time.Now().Add(-20 * time.Minute), false, ErrEditWindowEnd},
+		{"not the author", 6, "message", time.Now(), false, ErrNoAccess},
+		{"system message", 5, "join", time.Now(), false, ErrSystemMsg},
+		{"deleted message", 5, "message", time.Now(), true, ErrMsgNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			expectEditSelect(mock, tc.typ, tc.sent, tc.deleted)
+			mock.ExpectRollback()
+
+			if _, err := p.EditMsg(42, tc.uid, "new text", 15*time.Minute); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEditMsgMissing(t *testing.T) {
+	p, mock := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT room_id, user_id, type, text, timestamp`).
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "type", "text", "timestamp"}))
+	mock.ExpectRollback()
+
+	if _, err := p.EditMsg(42, 5, "new text", 15*time.Minute); !errors.Is(err, ErrMsgNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrMsgNotFound)
+	}
+}
+
+func expectDeleteSelect(mock sqlmock.Sqlmock, typ string, deleted, direct bool) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT m.room_id, m.user_id, m.type, m.timestamp`).
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "type", "timestamp", "deleted", "creator_id", "is_direct"}).
+			AddRow(1, 5, typ, time.Now(), deleted, 1, direct))
+}
+
+func TestDeleteMsgLeavesTombstone(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		uid  int64
+	}{
+		{"author", 5},
+		{"room creator", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			deletedAt := time.Now()
+			expectDeleteSelect(mock, "message", false, false)
+			mock.ExpectQuery(`UPDATE messages\s+SET deleted_at = CURRENT_TIMESTAMP, deleted_by = \$2`).
+				WithArgs(42, tc.uid).
+				WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+			mock.ExpectCommit()
+
+			msg, err := p.DeleteMsg(42, tc.uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.RoomID != 1 || msg.DeletedBy != tc.uid || msg.DeletedAt == nil || !msg.DeletedAt.Equal(deletedAt) {
+				t.Fatalf("unexpected message %+v", msg)
+			}
+		})
+	}
+}
+
+func TestDeleteMsgRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		uid     int64
+		typ     string
+		deleted bool
+		want    error
+	}{
+		{"another member", 6, "message", false, ErrNoAccess},
+		{"already deleted", 5, "message", true, ErrMsgNotFound},
+		{"system message", 5, "join", false, ErrSystemMsg},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			expectDeleteSelect(mock, tc.typ, tc.deleted, false)
+			mock.ExpectRollback()
+
+			if _, err := p.DeleteMsg(42, tc.uid); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+var msgRowColumns = []string{
+	"id", "room_id", "user_id", "type", "text", "timestamp", "client_id",
+	"edited_at", "deleted_at", "reply_to", "thread_root",
+}
+
+func TestGetMsgsHidesTombstoneText(t *testing.T) {
+	p, mock := newMock(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM messages\s+WHERE room_id = \$1\s+ORDER BY`).
+		WithArgs(1, Limit).
+		WillReturnRows(sqlmock.NewRows(msgRowColumns).
+			AddRow(43, 1, 5, "message", "kept", now, "", nil, nil, 0, 0).
+			AddRow(42, 1, 5, "message", "not yet purged", now, "", nil, now, 0, 0))
+	mock.ExpectQuery(`FROM message_reactions`).
+		WithArgs("{43}").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id", "emoji", "count"}))
+	mock.ExpectQuery(`SELECT thread_root, COUNT\(\*\), MAX\(timestamp\)`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"thread_root", "count", "max"}))
+
+	msgs, err := p.GetMsgs(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if msgs[0].Text != "kept" || msgs[0].DeletedAt != nil {
+		t.Fatalf("live message changed: %v", msgs[0])
+	}
+	if msgs[1].ID != 42 || msgs[1].Text != "" || msgs[1].DeletedAt == nil {
+		t.Fatalf("tombstone shows its text: %v", msgs[1])
+	}
+}
+
+func TestPurgeTombstones(t *testing.T) {
+	p, mock := newMock(t)
+	cutoff := time.Now().Add(-30 * 24 * time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM message_revisions`).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM message_reactions`).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE messages\s+SET text = NULL\s+WHERE deleted_at < \$1 AND text IS NOT NULL`).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := p.PurgeTombstones(context.Background(), cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 3 {
+		t.Fatalf("purged %d messages, want 3", purged)
+	}
+}
+
+func TestReactCounts(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		remove    bool
+		changed   bool
+		before    int64
+		wantCount int64
+	}{
+		{"new reaction", false, true, 2, 3},
+		{"repeated reaction", false, false, 3, 3},
+		{"removed reaction", true, true, 3, 2},
+		{"reaction that wasn't there", true, false, 2, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			mock.ExpectQuery(`WITH target AS`).
+				WithArgs(42, 5, "👍").
+				WillReturnRows(sqlmock.NewRows([]string{"room_id", "member", "changed", "count"}).
+					AddRow(1, true, tc.changed, tc.before))
+
+			react := p.AddReaction
+			if tc.remove {
+				react = p.RemoveReaction
+			}
+			roomID, count, changed, err := react(42, 5, "👍")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roomID != 1 || count != tc.wantCount || changed != tc.changed {
+				t.Fatalf("got room %d, count %d, changed %v", roomID, count, changed)
+			}
+		})
+	}
+}
+
+func TestReactRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		roomID any
+		member bool
+		want   error
+	}{
+		{"missing message", nil, false, ErrMsgNotFound},
+		{"not a member", 1, false, ErrNoAccess},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			mock.ExpectQuery(`WITH target AS`).
+				WithArgs(42, 6, "👍").
+				WillReturnRows(sqlmock.NewRows([]string{"room_id", "member", "changed", "count"}).
+					AddRow(tc.roomID, tc.member, false, 0))
+
+			if _, _, _, err := p.AddReaction(42, 6, "👍"); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetMsgsGroupsReactions(t *testing.T) {
+	p, mock := newMock(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM messages\s+WHERE room_id = \$1 AND id <= \$2`).
+		WithArgs(1, 43, Limit).
+		WillReturnRows(sqlmock.NewRows(msgRowColumns).
+			AddRow(43, 1, 5, "message", "hi", now, "", nil, nil, 0, 0).
+			AddRow(42, 1, 5, "message", "", now, "", nil, now, 0, 0).
+			AddRow(41, 1, 6, "message", "quiet", now, "", nil, nil, 0, 0))
+	mock.ExpectQuery(`FROM message_reactions`).
+		WithArgs("{43,41}").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id", "emoji", "count"}).
+			AddRow(43, "👍", 3).
+			AddRow(43, "🎉", 1))
+	mock.ExpectQuery(`SELECT thread_root, COUNT\(\*\), MAX\(timestamp\)`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"thread_root", "count", "max"}))
+
+	msgs, err := p.GetMsgs(1, 43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msgs[0].Reactions; len(got) != 2 || got["👍"] != 3 || got["🎉"] != 1 {
+		t.Fatalf("message 43 reactions %v", got)
+	}
+	if msgs[1].Reactions != nil || msgs[2].Reactions != nil {
+		t.Fatalf("reactions on messages without any: %v, %v", msgs[1].Reactions, msgs[2].Reactions)
+	}
+}
+
+func TestThreadRoot(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rows *sqlmock.Rows
+		want int64
+		err  error
+	}{
+		{"reply to a root", sqlmock.NewRows([]string{"root"}).AddRow(40), 40, nil},
+		{"missing message", sqlmock.NewRows([]string{"root"}), 0, ErrMsgNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			mock.ExpectQuery(`SELECT COALESCE\(thread_root, id\)`).
+				WithArgs(41, 1).
+				WillReturnRows(tc.rows)
+
+			root, err := p.ThreadRoot(1, 41)
+			if !errors.Is(err, tc.err) || root != tc.want {
+				t.Fatalf("got root %d, error %v", root, err)
+			}
+		})
+	}
+}
+
+func TestGetThreadUnknownRoot(t *testing.T) {
+	p, mock := newMock(t)
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(41, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	if _, err := p.GetThread(1, 41, 0); !errors.Is(err, ErrMsgNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrMsgNotFound)
+	}
+}
+
+func TestGetThreadPagesByID(t *testing.T) {
+	for _, lastID := range []int64{0, 45} {
+		p, mock := newMock(t)
+		now := time.Now()
+		mock.ExpectQuery(`SELECT EXISTS`).
+			WithArgs(40, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(`WHERE thread_root = \$1 AND \(\$2 = 0 OR id <= \$2\)\s+ORDER BY id DESC LIMIT \$3`).
+			WithArgs(40, lastID, Limit).
+			WillReturnRows(sqlmock.NewRows(msgRowColumns).
+				AddRow(44, 1, 6, "message", "second", now, "", nil, nil, 43, 40).
+				AddRow(43, 1, 5, "message", "first", now.Add(time.Second), "", nil, nil, 40, 40))
+		mock.ExpectQuery(`FROM message_reactions`).
+			WithArgs("{44,43}").
+			WillReturnRows(sqlmock.NewRows([]string{"message_id", "emoji", "count"}))
+
+		msgs, err := p.GetThread(1, 40, lastID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msgs) != 2 || msgs[0].ID != 44 || msgs[1].ID != 43 {
+			t.Fatalf("last id %d: unexpected page %v", lastID, msgs)
+		}
+		if msgs[0].ReplyTo != 43 || msgs[0].ThreadRoot != 40 || msgs[0].ReplyCount != 0 {
+			t.Fatalf("last id %d: unexpected reply %v", lastID, msgs[0])
+		}
+	}
+}
+
+func TestGetMsgsAddsReplyStats(t *testing.T) {
+	p, mock := newMock(t)
+	now := time.Now()
+	lastReply := now.Add(time.Minute)
+	mock.ExpectQuery(`FROM messages\s+WHERE room_id = \$1\s+ORDER BY`).
+		WithArgs(1, Limit).
+		WillReturnRows(sqlmock.NewRows(msgRowColumns).
+			AddRow(43, 1, 6, "message", "reply", now, "", nil, nil, 40, 40).
+			AddRow(41, 1, 5, "message", "no replies", now, "", nil, nil, 0, 0).
+			AddRow(40, 1, 5, "message", "root", now, "", nil, nil, 0, 0))
+	mock.ExpectQuery(`FROM message_reactions`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"message_id", "emoji", "count"}))
+	mock.ExpectQuery(`SELECT thread_root, COUNT\(\*\), MAX\(timestamp\)`).
+		WithArgs("{41,40}").
+		WillReturnRows(sqlmock.NewRows([]string{"thread_root", "count", "max"}).AddRow(40, 2, lastReply))
+
+	msgs, err := p.GetMsgs(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := msgs[2]
+	if root.ReplyCount != 2 || root.LastReplyAt == nil || !root.LastReplyAt.AsTime().Equal(lastReply) {
+		t.Fatalf("unexpected root stats %v", root)
+	}
+	if msgs[0].ReplyCount != 0 || msgs[0].LastReplyAt != nil || msgs[1].ReplyCount != 0 || msgs[1].LastReplyAt != nil {
+		t.Fatalf("stats on messages without replies: %v, %v", msgs[0], msgs[1])
+	}
+}
+
+func TestDeleteMsgInDirectRoom(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		uid  int64
+		want error
+	}{
+		{"author", 5, nil},
+		{"room creator", 1, ErrNoAccess},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, mock := newMock(t)
+			expectDeleteSelect(mock, "message", false, true)
+			if tc.want == nil {
+				mock.ExpectQuery(`UPDATE messages\s+SET deleted_at`).
+					WithArgs(42, tc.uid).
+					WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(time.Now()))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			if _, err := p.DeleteMsg(42, tc.uid); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
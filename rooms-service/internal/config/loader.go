@@ -33,8 +33,9 @@ type Redis struct {
 }
 
 type Kafka struct {
-	Brokers []string `yaml:"brokers"`
-	Topic   string   `yaml:"topic"`
+	Brokers         []string `yaml:"brokers"`
+	Topic           string   `yaml:"topic"`
+	UserEventsTopic string   `yaml:"user_events_topic"`
 }
 
 func (cfg *Config) Validate() error {
@@ -63,8 +64,9 @@ func Default() *Config {
 			TTL:  24 * time.Hour,
 		},
 		Kafka: &Kafka{
-			Brokers: []string{"kafka:9092"},
-			Topic:   "messages",
+			Brokers:         []string{"kafka:9092"},
+			Topic:           "messages",
+			UserEventsTopic: "user-events",
 		},
 	}
 }
@@ -24,6 +24,8 @@ type Rooms interface {
 	Get(ctx context.Context, roomID int64) (*models.Room, error)
 	UserIn(ctx context.Context, UID int64) ([]int64, error)
 	IsMember(ctx context.Context, UID, roomID int64) (bool, error)
+	GetOrCreateDirect(ctx context.Context, UID, peerUID int64) (int64, bool, error)
+	Directs(ctx context.Context, UID int64) ([]*models.Direct, error)
 	Ping(ctx context.Context)
 }
 
@@ -87,6 +89,7 @@ func (s *ServerAPI) Get(ctx context.Context, r *roomspb.GetRequest) (*roomspb.Ge
 			CreatorUID: room.CreatorUID,
 			IsPrivate:  room.IsPrivate,
 			CreatedAt:  timestamppb.New(room.CreatedAt),
+			IsDirect:   room.IsDirect,
 		}, nil
 	}
 }
@@ -113,6 +116,29 @@ func (s *ServerAPI) IsMember(ctx context.Context, r *roomspb.IsMemberRequest) (*
 	}
 }
 
+func (s *ServerAPI) GetOrCreateDirect(ctx context.Context, r *roomspb.DirectRequest) (*roomspb.DirectResponse, error) {
+	if roomID, created, err := s.rooms.GetOrCreateDirect(ctx, r.UID, r.PeerUID); err != nil {
+		if status_error.IsStatusError(err) {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "unexpected error: %s", err)
+	} else {
+		return &roomspb.DirectResponse{RoomID: roomID, Created: created}, nil
+	}
+}
+
+func (s *ServerAPI) Directs(ctx context.Context, r *roomspb.UserInRequest) (*roomspb.DirectsResponse, error) {
+	directs, err := s.rooms.Directs(ctx, r.UID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unexpected error: %s", err)
+	}
+	resp := &roomspb.DirectsResponse{Directs: make([]*roomspb.Direct, len(directs))}
+	for i, d := range directs {
+		resp.Directs[i] = &roomspb.Direct{RoomID: d.RoomID, PeerUID: d.PeerUID}
+	}
+	return resp, nil
+}
+
 func (s *ServerAPI) Ping(ctx context.Context, r *roomspb.Empty) (*roomspb.Empty, error) {
 	s.rooms.Ping(ctx)
 	return &roomspb.Empty{}, nil
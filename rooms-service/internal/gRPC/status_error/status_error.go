@@ -15,6 +15,8 @@ var (
 	NoAccess      = status.Error(codes.PermissionDenied, "only creator can invite to room")
 	Private       = status.Error(codes.PermissionDenied, "room is private")
 	AlreadyInRoom = status.Error(codes.PermissionDenied, "already in room")
+	Direct        = status.Error(codes.PermissionDenied, "direct room can't be joined or invited to")
+	DirectSelf    = status.Error(codes.InvalidArgument, "can't start a direct room with yourself")
 )
 
 func IsStatusError(err error) bool {
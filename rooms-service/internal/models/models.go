@@ -7,9 +7,15 @@ type Room struct {
 	RoomID     int64     `json:"RoomID"`
 	Name       string    `json:"Name"`
 	IsPrivate  bool      `json:"IsPrivate"`
+	IsDirect   bool      `json:"IsDirect"`
 	CreatedAt  time.Time `json:"CreatedAt"`
 }
 
+type Direct struct {
+	RoomID  int64 `json:"RoomID"`
+	PeerUID int64 `json:"PeerUID"`
+}
+
 type Message struct {
 	ID        int64     `json:"ID"`
 	RoomID    int64     `json:"RoomID"`
@@ -18,3 +24,17 @@ type Message struct {
 	Text      string    `json:"Text"`
 	Timestamp time.Time `json:"Timestamp"`
 }
+
+const (
+	UserEventInvited = "invited"
+	UserEventJoined  = "joined"
+	UserEventDirect  = "direct"
+)
+
+type UserEvent struct {
+	Type      string    `json:"Type"`
+	UID       int64     `json:"UID"`
+	RoomID    int64     `json:"RoomID,omitempty"`
+	ByUID     int64     `json:"ByUID,omitempty"`
+	Timestamp time.Time `json:"Timestamp"`
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/P3rCh1/chat-server/rooms-service/internal/config"
 	"github.com/P3rCh1/chat-server/rooms-service/internal/gRPC/status_error"
@@ -14,20 +15,29 @@ import (
 
 type RoomsService struct {
 	log  *slog.Logger
-	repo *repository.Repository
+	repo Storage
+}
+
+type Storage interface {
+	CreateRoom(ctx context.Context, room *models.Room) error
+	AddToRoom(ctx context.Context, uid, roomID int64) error
+	GetOrCreateDirect(ctx context.Context, uid, peerUID int64) (int64, bool, error)
+	GetDirects(ctx context.Context, uid int64) ([]*models.Direct, error)
+	NotifyUser(ev *models.UserEvent)
+	GetUserRooms(ctx context.Context, uid int64) ([]int64, error)
+	GetRoom(ctx context.Context, roomID int64) (*models.Room, error)
+	IsMember(ctx context.Context, uid, roomID int64) (bool, error)
+	Close()
 }
 
 func MustPrepare(log *slog.Logger, cfg *config.Config) *RoomsService {
 	const op = "user.MustPrepare"
-	room := &RoomsService{log: log}
-	var err error
-	room.repo, err = repository.New(log, cfg)
+	repo, err := repository.New(log, cfg)
 	if err != nil {
 		log.Error(op, "error", err)
-		room.Close()
 		os.Exit(1)
 	}
-	return room
+	return &RoomsService{log: log, repo: repo}
 }
 
 func (s *RoomsService) Close() {
@@ -55,7 +65,7 @@ func (s *RoomsService) Invite(
 	requesterUID, invitedUID, roomID int64,
 ) error {
 	const op = "user.Invite"
-	creatorID, err := s.repo.CreatorID(ctx, roomID)
+	room, err := s.repo.GetRoom(ctx, roomID)
 	if err != nil {
 		if status_error.IsStatusError(err) {
 			return err
@@ -63,7 +73,10 @@ func (s *RoomsService) Invite(
 		s.log.Error(op, "error", err)
 		return fmt.Errorf("get creator error: %w", err)
 	}
-	if creatorID != requesterUID {
+	if room.IsDirect {
+		return status_error.Direct
+	}
+	if room.CreatorUID != requesterUID {
 		return status_error.NoAccess
 	}
 	err = s.repo.AddToRoom(ctx, invitedUID, roomID)
@@ -73,6 +86,13 @@ func (s *RoomsService) Invite(
 		}
 		return fmt.Errorf("add to room error: %w", err)
 	}
+	s.repo.NotifyUser(&models.UserEvent{
+		Type:      models.UserEventInvited,
+		UID:       invitedUID,
+		RoomID:    roomID,
+		ByUID:     requesterUID,
+		Timestamp: time.Now(),
+	})
 	return nil
 }
 
@@ -81,7 +101,7 @@ func (s *RoomsService) Join(
 	UID, roomID int64,
 ) error {
 	const op = "user.Join"
-	isPrivate, err := s.repo.IsPrivate(ctx, roomID)
+	room, err := s.repo.GetRoom(ctx, roomID)
 	if err != nil {
 		if status_error.IsStatusError(err) {
 			return err
@@ -89,7 +109,10 @@ func (s *RoomsService) Join(
 		s.log.Error(op, "error", err)
 		return fmt.Errorf("get creator error: %w", err)
 	}
-	if isPrivate {
+	if room.IsDirect {
+		return status_error.Direct
+	}
+	if room.IsPrivate {
 		return status_error.Private
 	}
 	err = s.repo.AddToRoom(ctx, UID, roomID)
@@ -99,9 +122,58 @@ func (s *RoomsService) Join(
 		}
 		return fmt.Errorf("add to room error: %w", err)
 	}
+	s.repo.NotifyUser(&models.UserEvent{
+		Type:      models.UserEventJoined,
+		UID:       UID,
+		RoomID:    roomID,
+		Timestamp: time.Now(),
+	})
 	return nil
 }
 
+// GetOrCreateDirect returns the direct room of uid and peerUID. The peer is
+// notified when the room is created.
+func (s *RoomsService) GetOrCreateDirect(
+	ctx context.Context,
+	uid, peerUID int64,
+) (int64, bool, error) {
+	const op = "user.GetOrCreateDirect"
+	if uid == peerUID {
+		return 0, false, status_error.DirectSelf
+	}
+	roomID, created, err := s.repo.GetOrCreateDirect(ctx, uid, peerUID)
+	if err != nil {
+		if status_error.IsStatusError(err) {
+			return 0, false, err
+		}
+		s.log.Error(op, "error", err)
+		return 0, false, fmt.Errorf("get direct room error: %w", err)
+	}
+	if created {
+		s.repo.NotifyUser(&models.UserEvent{
+			Type:      models.UserEventDirect,
+			UID:       peerUID,
+			RoomID:    roomID,
+			ByUID:     uid,
+			Timestamp: time.Now(),
+		})
+	}
+	return roomID, created, nil
+}
+
+func (s *RoomsService) Directs(
+	ctx context.Context,
+	uid int64,
+) ([]*models.Direct, error) {
+	const op = "user.Directs"
+	directs, err := s.repo.GetDirects(ctx, uid)
+	if err != nil {
+		s.log.Error(op, "error", err)
+		return nil, fmt.Errorf("get direct rooms error: %w", err)
+	}
+	return directs, nil
+}
+
 func (s *RoomsService) Get(
 	ctx context.Context,
 	roomID int64,
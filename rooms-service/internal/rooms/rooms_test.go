@@ -0,0 +1,165 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/P3rCh1/chat-server/rooms-service/internal/gRPC/status_error"
+	"github.com/P3rCh1/chat-server/rooms-service/internal/models"
+)
+
+type memRepo struct {
+	rooms   map[int64]*models.Room
+	members map[int64]map[int64]bool
+	directs map[[2]int64]int64
+	events  []*models.UserEvent
+}
+
+func newMemRepo(rooms ...*models.Room) *memRepo {
+	r := &memRepo{
+		rooms:   make(map[int64]*models.Room),
+		members: make(map[int64]map[int64]bool),
+		directs: make(map[[2]int64]int64),
+	}
+	for _, room := range rooms {
+		r.CreateRoom(context.Background(), room)
+	}
+	return r
+}
+
+func (r *memRepo) CreateRoom(ctx context.Context, room *models.Room) error {
+	if room.RoomID == 0 {
+		room.RoomID = int64(len(r.rooms) + 1)
+	}
+	r.rooms[room.RoomID] = room
+	r.members[room.RoomID] = map[int64]bool{room.CreatorUID: true}
+	return nil
+}
+
+func (r *memRepo) AddToRoom(ctx context.Context, uid, roomID int64) error {
+	if r.members[roomID][uid] {
+		return status_error.AlreadyInRoom
+	}
+	r.members[roomID][uid] = true
+	return nil
+}
+
+func (r *memRepo) GetOrCreateDirect(ctx context.Context, uid, peerUID int64) (int64, bool, error) {
+	key := [2]int64{min(uid, peerUID), max(uid, peerUID)}
+	if roomID, ok := r.directs[key]; ok {
+		return roomID, false, nil
+	}
+	room := &models.Room{CreatorUID: uid, IsPrivate: true, IsDirect: true}
+	r.CreateRoom(ctx, room)
+	r.members[room.RoomID][peerUID] = true
+	r.directs[key] = room.RoomID
+	return room.RoomID, true, nil
+}
+
+func (r *memRepo) GetDirects(ctx context.Context, uid int64) ([]*models.Direct, error) {
+	var directs []*models.Direct
+	for key, roomID := range r.directs {
+		switch uid {
+		case key[0]:
+			directs = append(directs, &models.Direct{RoomID: roomID, PeerUID: key[1]})
+		case key[1]:
+			directs = append(directs, &models.Direct{RoomID: roomID, PeerUID: key[0]})
+		}
+	}
+	return directs, nil
+}
+
+func (r *memRepo) NotifyUser(ev *models.UserEvent) {
+	r.events = append(r.events, ev)
+}
+
+func (r *memRepo) GetUserRooms(ctx context.Context, uid int64) ([]int64, error) {
+	var rooms []int64
+	for roomID, members := range r.members {
+		if members[uid] {
+			rooms = append(rooms, roomID)
+		}
+	}
+	return rooms, nil
+}
+
+func (r *memRepo) GetRoom(ctx context.Context, roomID int64) (*models.Room, error) {
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return nil, status_error.RoomNotFound
+	}
+	return room, nil
+}
+
+func (r *memRepo) IsMember(ctx context.Context, uid, roomID int64) (bool, error) {
+	return r.members[roomID][uid], nil
+}
+
+func (r *memRepo) Close() {}
+
+func TestDirectRoomRefusesNewMembers(t *testing.T) {
+	repo := newMemRepo()
+	s := &RoomsService{log: slog.New(slog.NewTextHandler(io.Discard, nil)), repo: repo}
+	ctx := context.Background()
+	roomID, _, err := s.GetOrCreateDirect(ctx, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.events = nil
+
+	if err := s.Join(ctx, 3, roomID); !errors.Is(err, status_error.Direct) {
+		t.Errorf("join: got %v, want %v", err, status_error.Direct)
+	}
+	if err := s.Invite(ctx, 1, 3, roomID); !errors.Is(err, status_error.Direct) {
+		t.Errorf("invite: got %v, want %v", err, status_error.Direct)
+	}
+	if member, _ := repo.IsMember(ctx, 3, roomID); member || len(repo.events) != 0 {
+		t.Fatalf("a third user got in: member %v, events %v", member, repo.events)
+	}
+}
+
+func TestJoinPublicRoom(t *testing.T) {
+	repo := newMemRepo(&models.Room{RoomID: 5, CreatorUID: 1})
+	s := &RoomsService{log: slog.New(slog.NewTextHandler(io.Discard, nil)), repo: repo}
+	if err := s.Join(context.Background(), 3, 5); err != nil {
+		t.Fatal(err)
+	}
+	if member, _ := repo.IsMember(context.Background(), 3, 5); !member {
+		t.Fatal("user is not a member after joining")
+	}
+	if len(repo.events) != 1 || repo.events[0].Type != models.UserEventJoined {
+		t.Fatalf("unexpected events %v", repo.events)
+	}
+}
+
+func TestGetOrCreateDirect(t *testing.T) {
+	repo := newMemRepo()
+	s := &RoomsService{log: slog.New(slog.NewTextHandler(io.Discard, nil)), repo: repo}
+	ctx := context.Background()
+
+	if _, _, err := s.GetOrCreateDirect(ctx, 1, 1); !errors.Is(err, status_error.DirectSelf) {
+		t.Fatalf("with yourself: got %v, want %v", err, status_error.DirectSelf)
+	}
+
+	roomID, created, err := s.GetOrCreateDirect(ctx, 1, 7)
+	if err != nil || !created {
+		t.Fatalf("first call: created %v, error %v", created, err)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("unexpected events %v", repo.events)
+	}
+	if ev := repo.events[0]; ev.Type != models.UserEventDirect || ev.UID != 7 || ev.ByUID != 1 || ev.RoomID != roomID {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	again, created, err := s.GetOrCreateDirect(ctx, 7, 1)
+	if err != nil || created || again != roomID {
+		t.Fatalf("second call: room %d, created %v, error %v", again, created, err)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("existing room notified again: %v", repo.events)
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/P3rCh1/chat-server/rooms-service/internal/config"
@@ -30,6 +31,18 @@ func migrate(ctx context.Context, db *sql.DB) error {
     		room_id INTEGER REFERENCES rooms(id),
     		PRIMARY KEY (user_id, room_id)
 		);
+
+		ALTER TABLE rooms ALTER COLUMN name DROP NOT NULL;
+		ALTER TABLE rooms ADD COLUMN IF NOT EXISTS is_direct BOOLEAN NOT NULL DEFAULT false;
+
+		CREATE TABLE IF NOT EXISTS direct_rooms (
+			room_id INTEGER PRIMARY KEY REFERENCES rooms(id),
+			uid_low INTEGER REFERENCES users(id) NOT NULL,
+			uid_high INTEGER REFERENCES users(id) NOT NULL,
+			UNIQUE (uid_low, uid_high)
+		);
+
+		CREATE INDEX IF NOT EXISTS direct_rooms_uid_high_idx ON direct_rooms (uid_high);
 	`
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -97,6 +110,111 @@ func (p *Postgres) CreateRoom(ctx context.Context, room *models.Room) error {
 	return nil
 }
 
+// GetOrCreateDirect returns the direct room of two users, creating it with
+// both of them as members on first use. Concurrent first calls end up with
+// the same room: the loser of the direct_rooms insert rolls back and reads
+// the winner's room.
+func (p *Postgres) GetOrCreateDirect(ctx context.Context, uid, peerUID int64) (int64, bool, error) {
+	low, high := min(uid, peerUID), max(uid, peerUID)
+	roomID, err := p.directRoom(ctx, low, high)
+	if err == nil {
+		return roomID, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+	const queryRoom = `
+		INSERT INTO rooms (name, is_private, creator_id, is_direct)
+		VALUES (NULL, true, $1, true)
+		RETURNING id
+	`
+	const queryDirect = `
+		INSERT INTO direct_rooms (room_id, uid_low, uid_high)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (uid_low, uid_high) DO NOTHING
+	`
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	err = tx.QueryRowContext(ctx, queryRoom, uid).Scan(&roomID)
+	if err != nil {
+		if statErr := ExpectedPGErr(err, status_error.UserNotFound, nil); statErr != nil {
+			return 0, false, statErr
+		}
+		return 0, false, fmt.Errorf("failed to create direct room: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, queryDirect, roomID, low, high)
+	if err != nil {
+		if statErr := ExpectedPGErr(err, status_error.UserNotFound, nil); statErr != nil {
+			return 0, false, statErr
+		}
+		return 0, false, fmt.Errorf("failed to create direct room: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, false, fmt.Errorf("failed to create direct room: %w", err)
+	} else if n == 0 {
+		tx.Rollback()
+		roomID, err = p.directRoom(ctx, low, high)
+		return roomID, false, err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO room_members (user_id, room_id) VALUES ($1, $3), ($2, $3)",
+		low, high, roomID,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to add members to direct room: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit: %w", err)
+	}
+	return roomID, true, nil
+}
+
+func (p *Postgres) directRoom(ctx context.Context, low, high int64) (int64, error) {
+	const query = `
+		SELECT room_id FROM direct_rooms WHERE uid_low = $1 AND uid_high = $2
+	`
+	var roomID int64
+	err := p.db.QueryRowContext(ctx, query, low, high).Scan(&roomID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to find direct room: %w", err)
+	}
+	return roomID, nil
+}
+
+func (p *Postgres) GetDirects(ctx context.Context, uid int64) ([]*models.Direct, error) {
+	const query = `
+		SELECT room_id, uid_high FROM direct_rooms WHERE uid_low = $1
+		UNION ALL
+		SELECT room_id, uid_low FROM direct_rooms WHERE uid_high = $1
+	`
+	rows, err := p.db.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user`s direct rooms: %w", err)
+	}
+	defer rows.Close()
+	var directs []*models.Direct
+	for rows.Next() {
+		d := &models.Direct{}
+		if err := rows.Scan(&d.RoomID, &d.PeerUID); err != nil {
+			return nil, fmt.Errorf("failed to scan direct room: %w", err)
+		}
+		directs = append(directs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read direct rooms: %w", err)
+	}
+	return directs, nil
+}
+
 func (p *Postgres) CreatorID(ctx context.Context, roomID int64) (int64, error) {
 	const query = `
 		SELECT creator_id FROM rooms WHERE id = $1
@@ -200,7 +318,7 @@ func (p *Postgres) GetUserRooms(ctx context.Context, uid int64) ([]int64, error)
 
 func (p *Postgres) GetRoom(ctx context.Context, roomID int64) (*models.Room, error) {
 	const query = `
-        SELECT name, is_private, creator_id, created_at FROM rooms WHERE id = $1
+        SELECT COALESCE(name, ''), is_private, is_direct, creator_id, created_at FROM rooms WHERE id = $1
     `
 	room := &models.Room{
 		RoomID: roomID,
@@ -208,6 +326,7 @@ func (p *Postgres) GetRoom(ctx context.Context, roomID int64) (*models.Room, err
 	err := p.db.QueryRowContext(ctx, query, roomID).Scan(
 		&room.Name,
 		&room.IsPrivate,
+		&room.IsDirect,
 		&room.CreatorUID,
 		&room.CreatedAt,
 	)
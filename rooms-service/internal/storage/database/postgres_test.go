@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newMock(t *testing.T) (*Postgres, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return &Postgres{db}, mock
+}
+
+func expectDirectLookup(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery(`SELECT room_id FROM direct_rooms WHERE uid_low = \$1 AND uid_high = \$2`).
+		WithArgs(3, 7).
+		WillReturnRows(rows)
+}
+
+func TestGetOrCreateDirectFindsExistingRoom(t *testing.T) {
+	for _, pair := range [][2]int64{{3, 7}, {7, 3}} {
+		p, mock := newMock(t)
+		expectDirectLookup(mock, sqlmock.NewRows([]string{"room_id"}).AddRow(9))
+
+		roomID, created, err := p.GetOrCreateDirect(context.Background(), pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if roomID != 9 || created {
+			t.Fatalf("%v: got room %d, created %v", pair, roomID, created)
+		}
+	}
+}
+
+func TestGetOrCreateDirectCreatesRoom(t *testing.T) {
+	p, mock := newMock(t)
+	expectDirectLookup(mock, sqlmock.NewRows([]string{"room_id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO rooms \(name, is_private, creator_id, is_direct\)`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectExec(`INSERT INTO direct_rooms`).
+		WithArgs(9, 3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO room_members`).
+		WithArgs(3, 7, 9).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	roomID, created, err := p.GetOrCreateDirect(context.Background(), 7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roomID != 9 || !created {
+		t.Fatalf("got room %d, created %v", roomID, created)
+	}
+}
+
+// TestGetOrCreateDirectLosesRace covers two first calls at once: the one
+// whose direct_rooms insert conflicts drops its room and returns the other's.
+func TestGetOrCreateDirectLosesRace(t *testing.T) {
+	p, mock := newMock(t)
+	expectDirectLookup(mock, sqlmock.NewRows([]string{"room_id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO rooms`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectExec(`INSERT INTO direct_rooms`).
+		WithArgs(10, 3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	expectDirectLookup(mock, sqlmock.NewRows([]string{"room_id"}).AddRow(9))
+
+	roomID, created, err := p.GetOrCreateDirect(context.Background(), 3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roomID != 9 || created {
+		t.Fatalf("got room %d, created %v", roomID, created)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/P3rCh1/chat-server/rooms-service/internal/config"
 	"github.com/P3rCh1/chat-server/rooms-service/internal/models"
@@ -11,7 +12,8 @@ import (
 )
 
 type Producer struct {
-	w *kafka.Writer
+	w      *kafka.Writer
+	events *kafka.Writer
 }
 
 func NewProducer(cfg *config.Kafka) *Producer {
@@ -24,6 +26,13 @@ func NewProducer(cfg *config.Kafka) *Producer {
 			BatchSize:        1,
 			BatchTimeout:     0,
 		}),
+		events: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      cfg.Brokers,
+			Topic:        cfg.UserEventsTopic,
+			Balancer:     &kafka.Hash{},
+			BatchSize:    1,
+			BatchTimeout: 0,
+		}),
 	}
 }
 
@@ -39,3 +48,18 @@ func (p *Producer) Send(ctx context.Context, msg *models.Message) error {
 	}
 	return p.w.WriteMessages(ctx, kafkaMessage)
 }
+
+// SendUserEvent publishes ev to the user events topic, keyed by the user it
+// is addressed to.
+func (p *Producer) SendUserEvent(ctx context.Context, ev *models.UserEvent) error {
+	bytes, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user event: %w", err)
+	}
+	kafkaMessage := kafka.Message{
+		Key:   []byte(strconv.FormatInt(ev.UID, 10)),
+		Value: bytes,
+		Time:  ev.Timestamp,
+	}
+	return p.events.WriteMessages(ctx, kafkaMessage)
+}
@@ -93,6 +93,40 @@ func (r *Repository) AddToRoom(ctx context.Context, uid, roomID int64) error {
 	return nil
 }
 
+func (r *Repository) GetOrCreateDirect(ctx context.Context, uid, peerUID int64) (int64, bool, error) {
+	roomID, created, err := r.psql.GetOrCreateDirect(ctx, uid, peerUID)
+	if err != nil || !created {
+		return roomID, created, err
+	}
+	for _, member := range []int64{uid, peerUID} {
+		err := r.roomMembers.AddSingle(ctx, member, roomID)
+		if err != nil && err != cache.NotFound {
+			r.log.Error("add to direct room redis fail", "error", err)
+		}
+	}
+	return roomID, true, nil
+}
+
+func (r *Repository) GetDirects(ctx context.Context, uid int64) ([]*models.Direct, error) {
+	return r.psql.GetDirects(ctx, uid)
+}
+
+// NotifyUser publishes ev in the background; the gateway delivers it to every
+// connection of ev.UID.
+func (r *Repository) NotifyUser(ev *models.UserEvent) {
+	go func() {
+		err := r.kafka.SendUserEvent(context.Background(), ev)
+		if err != nil {
+			r.log.Error(
+				"kafka send user event",
+				"error", err,
+				"type", ev.Type,
+				"UID", ev.UID,
+			)
+		}
+	}()
+}
+
 func (r *Repository) IsPrivate(ctx context.Context, roomID int64) (bool, error) {
 	room, err := r.GetRoom(ctx, roomID)
 	if err != nil {
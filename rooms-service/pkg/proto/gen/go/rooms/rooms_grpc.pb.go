@@ -19,13 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Rooms_Invite_FullMethodName   = "/roomspb.rooms/Invite"
-	Rooms_Join_FullMethodName     = "/roomspb.rooms/Join"
-	Rooms_Create_FullMethodName   = "/roomspb.rooms/Create"
-	Rooms_Get_FullMethodName      = "/roomspb.rooms/Get"
-	Rooms_UserIn_FullMethodName   = "/roomspb.rooms/UserIn"
-	Rooms_IsMember_FullMethodName = "/roomspb.rooms/IsMember"
-	Rooms_Ping_FullMethodName     = "/roomspb.rooms/Ping"
+	Rooms_Invite_FullMethodName            = "/roomspb.rooms/Invite"
+	Rooms_Join_FullMethodName              = "/roomspb.rooms/Join"
+	Rooms_Create_FullMethodName            = "/roomspb.rooms/Create"
+	Rooms_Get_FullMethodName               = "/roomspb.rooms/Get"
+	Rooms_UserIn_FullMethodName            = "/roomspb.rooms/UserIn"
+	Rooms_IsMember_FullMethodName          = "/roomspb.rooms/IsMember"
+	Rooms_GetOrCreateDirect_FullMethodName = "/roomspb.rooms/GetOrCreateDirect"
+	Rooms_Directs_FullMethodName           = "/roomspb.rooms/Directs"
+	Rooms_Ping_FullMethodName              = "/roomspb.rooms/Ping"
 )
 
 // RoomsClient is the client API for Rooms service.
@@ -38,6 +40,8 @@ type RoomsClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	UserIn(ctx context.Context, in *UserInRequest, opts ...grpc.CallOption) (*UserInResponse, error)
 	IsMember(ctx context.Context, in *IsMemberRequest, opts ...grpc.CallOption) (*IsMemberResponse, error)
+	GetOrCreateDirect(ctx context.Context, in *DirectRequest, opts ...grpc.CallOption) (*DirectResponse, error)
+	Directs(ctx context.Context, in *UserInRequest, opts ...grpc.CallOption) (*DirectsResponse, error)
 	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
 }
 
@@ -109,6 +113,26 @@ func (c *roomsClient) IsMember(ctx context.Context, in *IsMemberRequest, opts ..
 	return out, nil
 }
 
+func (c *roomsClient) GetOrCreateDirect(ctx context.Context, in *DirectRequest, opts ...grpc.CallOption) (*DirectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DirectResponse)
+	err := c.cc.Invoke(ctx, Rooms_GetOrCreateDirect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomsClient) Directs(ctx context.Context, in *UserInRequest, opts ...grpc.CallOption) (*DirectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DirectsResponse)
+	err := c.cc.Invoke(ctx, Rooms_Directs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *roomsClient) Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Empty)
@@ -129,6 +153,8 @@ type RoomsServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	UserIn(context.Context, *UserInRequest) (*UserInResponse, error)
 	IsMember(context.Context, *IsMemberRequest) (*IsMemberResponse, error)
+	GetOrCreateDirect(context.Context, *DirectRequest) (*DirectResponse, error)
+	Directs(context.Context, *UserInRequest) (*DirectsResponse, error)
 	Ping(context.Context, *Empty) (*Empty, error)
 	mustEmbedUnimplementedRoomsServer()
 }
@@ -158,6 +184,12 @@ func (UnimplementedRoomsServer) UserIn(context.Context, *UserInRequest) (*UserIn
 func (UnimplementedRoomsServer) IsMember(context.Context, *IsMemberRequest) (*IsMemberResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsMember not implemented")
 }
+func (UnimplementedRoomsServer) GetOrCreateDirect(context.Context, *DirectRequest) (*DirectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateDirect not implemented")
+}
+func (UnimplementedRoomsServer) Directs(context.Context, *UserInRequest) (*DirectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Directs not implemented")
+}
 func (UnimplementedRoomsServer) Ping(context.Context, *Empty) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
@@ -290,6 +322,42 @@ func _Rooms_IsMember_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Rooms_GetOrCreateDirect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DirectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomsServer).GetOrCreateDirect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rooms_GetOrCreateDirect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomsServer).GetOrCreateDirect(ctx, req.(*DirectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rooms_Directs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomsServer).Directs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rooms_Directs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomsServer).Directs(ctx, req.(*UserInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Rooms_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -339,6 +407,14 @@ var Rooms_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IsMember",
 			Handler:    _Rooms_IsMember_Handler,
 		},
+		{
+			MethodName: "GetOrCreateDirect",
+			Handler:    _Rooms_GetOrCreateDirect_Handler,
+		},
+		{
+			MethodName: "Directs",
+			Handler:    _Rooms_Directs_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _Rooms_Ping_Handler,
@@ -2,11 +2,15 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/P3rCh1/chat-server/session/internal/session"
 	sessionpb "github.com/P3rCh1/chat-server/session/pkg/proto/gen/go/session"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type serverAPI struct {
@@ -15,7 +19,7 @@ type serverAPI struct {
 }
 
 type Session interface {
-	Verify(ctx context.Context, token string) (int64, error)
+	Verify(ctx context.Context, token string) (int64, time.Time, error)
 	Generate(ctx context.Context, uid int64) (string, error)
 	Ping(ctx context.Context)
 }
@@ -28,11 +32,17 @@ func (s *serverAPI) Verify(ctx context.Context, r *sessionpb.VerifyRequest) (
 	*sessionpb.VerifyResponse,
 	error,
 ) {
-	id, err := s.session.Verify(ctx, r.GetToken())
+	id, expiresAt, err := s.session.Verify(ctx, r.GetToken())
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "invalid token")
+		if errors.Is(err, session.ErrTokenExpired) {
+			return nil, status.Error(codes.Unauthenticated, "token expired")
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
-	return &sessionpb.VerifyResponse{UID: id}, nil
+	return &sessionpb.VerifyResponse{
+		UID:       id,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
 }
 
 func (s *serverAPI) Generate(ctx context.Context, r *sessionpb.GenerateRequest) (
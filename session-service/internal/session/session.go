@@ -37,28 +37,32 @@ func (s *SessionService) Generate(ctx context.Context, id int64) (string, error)
 	return token.SignedString(s.Secret)
 }
 
-func (s *SessionService) Verify(ctx context.Context, tokenString string) (int64, error) {
+func (s *SessionService) Verify(ctx context.Context, tokenString string) (int64, time.Time, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		return s.Secret, nil
-	})
+	}, jwt.WithExpirationRequired())
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, ErrTokenExpired
+			return 0, time.Time{}, ErrTokenExpired
 		}
-		return 0, ErrInvalidToken
+		return 0, time.Time{}, ErrInvalidToken
 	}
 	if !token.Valid {
-		return 0, ErrInvalidToken
+		return 0, time.Time{}, ErrInvalidToken
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, ErrInvalidToken
+		return 0, time.Time{}, ErrInvalidToken
 	}
 	uid, ok := claims["UID"].(float64)
 	if !ok {
-		return 0, ErrInvalidToken
+		return 0, time.Time{}, ErrInvalidToken
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return 0, time.Time{}, ErrInvalidToken
 	}
-	return int64(uid), nil
+	return int64(uid), exp.Time, nil
 }
 
 func (s *SessionService) Ping(ctx context.Context) {
@@ -14,6 +14,7 @@ type Config struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 	Postgres        *Postgres     `yaml:"postgres"`
 	Redis           *Redis        `yaml:"redis"`
+	Kafka           *Kafka        `yaml:"kafka"`
 	SessionAddr     string        `yaml:"session_addr"`
 }
 
@@ -33,6 +34,11 @@ type Redis struct {
 	Password string
 }
 
+type Kafka struct {
+	Brokers         []string `yaml:"brokers"`
+	UserEventsTopic string   `yaml:"user_events_topic"`
+}
+
 func (cfg *Config) Validate() error {
 	if cfg.Postgres.Password == "" {
 		return errors.New("postgres password is required")
@@ -58,6 +64,10 @@ func Default() *Config {
 			Addr: "redis:6379",
 			TTL:  24 * time.Hour,
 		},
+		Kafka: &Kafka{
+			Brokers:         []string{"kafka:9092"},
+			UserEventsTopic: "user-events",
+		},
 	}
 }
 
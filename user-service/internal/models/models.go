@@ -8,3 +8,12 @@ type Profile struct {
 	Email     string
 	CreatedAt time.Time
 }
+
+const UserEventNameChanged = "name_changed"
+
+type UserEvent struct {
+	Type      string    `json:"Type"`
+	UID       int64     `json:"UID"`
+	Name      string    `json:"Name,omitempty"`
+	Timestamp time.Time `json:"Timestamp"`
+}
@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/P3rCh1/chat-server/user-service/internal/config"
+	"github.com/P3rCh1/chat-server/user-service/internal/models"
+	"github.com/segmentio/kafka-go"
+)
+
+type Producer struct {
+	w *kafka.Writer
+}
+
+func NewProducer(cfg *config.Kafka) *Producer {
+	return &Producer{
+		w: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      cfg.Brokers,
+			Topic:        cfg.UserEventsTopic,
+			Balancer:     &kafka.Hash{},
+			BatchSize:    1,
+			BatchTimeout: 0,
+		}),
+	}
+}
+
+func (p *Producer) SendUserEvent(ctx context.Context, ev *models.UserEvent) error {
+	bytes, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user event: %w", err)
+	}
+	kafkaMessage := kafka.Message{
+		Key:   []byte(strconv.FormatInt(ev.UID, 10)),
+		Value: bytes,
+		Time:  ev.Timestamp,
+	}
+	return p.w.WriteMessages(ctx, kafkaMessage)
+}
+
+func (p *Producer) Close() error {
+	return p.w.Close()
+}
@@ -7,12 +7,14 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/P3rCh1/chat-server/user-service/internal/config"
 	"github.com/P3rCh1/chat-server/user-service/internal/gRPC/status_error"
 	"github.com/P3rCh1/chat-server/user-service/internal/models"
 	"github.com/P3rCh1/chat-server/user-service/internal/storage/cache"
 	"github.com/P3rCh1/chat-server/user-service/internal/storage/database"
+	"github.com/P3rCh1/chat-server/user-service/internal/storage/kafka"
 	sessionpb "github.com/P3rCh1/chat-server/user-service/pkg/proto/gen/go/session"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -22,6 +24,7 @@ type UserService struct {
 	log           *slog.Logger
 	psql          *database.Postgres
 	redis         *cache.Cacher
+	kafka         *kafka.Producer
 	sessionClient sessionpb.SessionClient
 	sessionConn   *grpc.ClientConn
 }
@@ -40,6 +43,7 @@ func MustPrepare(log *slog.Logger, cfg *config.Config) *UserService {
 		defer wg.Done()
 		user.redis, errCache = cache.New(cfg.Redis)
 	}()
+	user.kafka = kafka.NewProducer(cfg.Kafka)
 	user.sessionConn, errListen = grpc.NewClient(cfg.SessionAddr, grpc.WithTransportCredentials(insecure.NewCredentials())) //TODO to config
 	if errListen == nil {
 		user.sessionClient = sessionpb.NewSessionClient(user.sessionConn)
@@ -63,12 +67,16 @@ func (s *UserService) CloseNotNil() {
 	if s.sessionConn != nil {
 		s.sessionConn.Close()
 	}
+	if s.kafka != nil {
+		s.kafka.Close()
+	}
 }
 
 func (s *UserService) Close() {
 	s.psql.Close()
 	s.redis.Close()
 	s.sessionConn.Close()
+	s.kafka.Close()
 }
 
 func (s *UserService) Register(
@@ -155,6 +163,17 @@ func (s *UserService) ChangeName(
 			s.log.Error(op, "error", err)
 		}
 	}()
+	go func() {
+		err := s.kafka.SendUserEvent(context.Background(), &models.UserEvent{
+			Type:      models.UserEventNameChanged,
+			UID:       id,
+			Name:      newName,
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			s.log.Error(op, "error", err)
+		}
+	}()
 	return nil
 }
 
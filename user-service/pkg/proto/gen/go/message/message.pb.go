@@ -28,6 +28,8 @@ type SendRequest struct {
 	UID           int64                  `protobuf:"varint,2,opt,name=UID,proto3" json:"UID,omitempty"`
 	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
 	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	ClientID      string                 `protobuf:"bytes,5,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ReplyTo       int64                  `protobuf:"varint,6,opt,name=replyTo,proto3" json:"replyTo,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,10 +92,25 @@ func (x *SendRequest) GetText() string {
 	return ""
 }
 
+func (x *SendRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *SendRequest) GetReplyTo() int64 {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return 0
+}
+
 type SendResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ID            int64                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -142,6 +159,13 @@ func (x *SendResponse) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *SendResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
 type Message struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ID            int64                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
@@ -150,6 +174,14 @@ type Message struct {
 	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
 	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
 	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ClientID      string                 `protobuf:"bytes,7,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=editedAt,proto3" json:"editedAt,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	Reactions     map[string]int64       `protobuf:"bytes,10,rep,name=reactions,proto3" json:"reactions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ReplyTo       int64                  `protobuf:"varint,11,opt,name=replyTo,proto3" json:"replyTo,omitempty"`
+	ThreadRoot    int64                  `protobuf:"varint,12,opt,name=threadRoot,proto3" json:"threadRoot,omitempty"`
+	ReplyCount    int64                  `protobuf:"varint,13,opt,name=replyCount,proto3" json:"replyCount,omitempty"`
+	LastReplyAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=lastReplyAt,proto3" json:"lastReplyAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -226,6 +258,62 @@ func (x *Message) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Message) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *Message) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *Message) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Message) GetReactions() map[string]int64 {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *Message) GetReplyTo() int64 {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return 0
+}
+
+func (x *Message) GetThreadRoot() int64 {
+	if x != nil {
+		return x.ThreadRoot
+	}
+	return 0
+}
+
+func (x *Message) GetReplyCount() int64 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Message) GetLastReplyAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReplyAt
+	}
+	return nil
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
@@ -278,6 +366,66 @@ func (x *GetRequest) GetLastID() int64 {
 	return 0
 }
 
+type GetThreadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	ThreadRoot    int64                  `protobuf:"varint,2,opt,name=threadRoot,proto3" json:"threadRoot,omitempty"`
+	LastID        int64                  `protobuf:"varint,3,opt,name=lastID,proto3" json:"lastID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThreadRequest) Reset() {
+	*x = GetThreadRequest{}
+	mi := &file_message_message_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThreadRequest) ProtoMessage() {}
+
+func (x *GetThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThreadRequest.ProtoReflect.Descriptor instead.
+func (*GetThreadRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetThreadRequest) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *GetThreadRequest) GetThreadRoot() int64 {
+	if x != nil {
+		return x.ThreadRoot
+	}
+	return 0
+}
+
+func (x *GetThreadRequest) GetLastID() int64 {
+	if x != nil {
+		return x.LastID
+	}
+	return 0
+}
+
 type GetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
@@ -287,7 +435,7 @@ type GetResponse struct {
 
 func (x *GetResponse) Reset() {
 	*x = GetResponse{}
-	mi := &file_message_message_proto_msgTypes[4]
+	mi := &file_message_message_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -299,7 +447,7 @@ func (x *GetResponse) String() string {
 func (*GetResponse) ProtoMessage() {}
 
 func (x *GetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_message_message_proto_msgTypes[4]
+	mi := &file_message_message_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,7 +460,7 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return file_message_message_proto_rawDescGZIP(), []int{4}
+	return file_message_message_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetResponse) GetMessages() []*Message {
@@ -322,6 +470,534 @@ func (x *GetResponse) GetMessages() []*Message {
 	return nil
 }
 
+type MarkReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	UID           int64                  `protobuf:"varint,2,opt,name=UID,proto3" json:"UID,omitempty"`
+	MessageID     int64                  `protobuf:"varint,3,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadRequest) Reset() {
+	*x = MarkReadRequest{}
+	mi := &file_message_message_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadRequest) ProtoMessage() {}
+
+func (x *MarkReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkReadRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MarkReadRequest) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *MarkReadRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *MarkReadRequest) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+type MarkReadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastReadID    int64                  `protobuf:"varint,1,opt,name=lastReadID,proto3" json:"lastReadID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadResponse) Reset() {
+	*x = MarkReadResponse{}
+	mi := &file_message_message_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadResponse) ProtoMessage() {}
+
+func (x *MarkReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkReadResponse) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MarkReadResponse) GetLastReadID() int64 {
+	if x != nil {
+		return x.LastReadID
+	}
+	return 0
+}
+
+type UnreadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UID           int64                  `protobuf:"varint,1,opt,name=UID,proto3" json:"UID,omitempty"`
+	RoomIDs       []int64                `protobuf:"varint,2,rep,packed,name=roomIDs,proto3" json:"roomIDs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnreadRequest) Reset() {
+	*x = UnreadRequest{}
+	mi := &file_message_message_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnreadRequest) ProtoMessage() {}
+
+func (x *UnreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnreadRequest.ProtoReflect.Descriptor instead.
+func (*UnreadRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UnreadRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *UnreadRequest) GetRoomIDs() []int64 {
+	if x != nil {
+		return x.RoomIDs
+	}
+	return nil
+}
+
+type UnreadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counts        map[int64]int64        `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnreadResponse) Reset() {
+	*x = UnreadResponse{}
+	mi := &file_message_message_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnreadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnreadResponse) ProtoMessage() {}
+
+func (x *UnreadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnreadResponse.ProtoReflect.Descriptor instead.
+func (*UnreadResponse) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UnreadResponse) GetCounts() map[int64]int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type EditRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageID     int64                  `protobuf:"varint,1,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	UID           int64                  `protobuf:"varint,2,opt,name=UID,proto3" json:"UID,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditRequest) Reset() {
+	*x = EditRequest{}
+	mi := &file_message_message_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditRequest) ProtoMessage() {}
+
+func (x *EditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditRequest.ProtoReflect.Descriptor instead.
+func (*EditRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EditRequest) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *EditRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *EditRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type EditResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=editedAt,proto3" json:"editedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditResponse) Reset() {
+	*x = EditResponse{}
+	mi := &file_message_message_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditResponse) ProtoMessage() {}
+
+func (x *EditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditResponse.ProtoReflect.Descriptor instead.
+func (*EditResponse) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EditResponse) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *EditResponse) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageID     int64                  `protobuf:"varint,1,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	UID           int64                  `protobuf:"varint,2,opt,name=UID,proto3" json:"UID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_message_message_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteRequest) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_message_message_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteResponse) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *DeleteResponse) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type ReactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageID     int64                  `protobuf:"varint,1,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	UID           int64                  `protobuf:"varint,2,opt,name=UID,proto3" json:"UID,omitempty"`
+	Emoji         string                 `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionRequest) Reset() {
+	*x = ReactionRequest{}
+	mi := &file_message_message_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionRequest) ProtoMessage() {}
+
+func (x *ReactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionRequest.ProtoReflect.Descriptor instead.
+func (*ReactionRequest) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReactionRequest) GetMessageID() int64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *ReactionRequest) GetUID() int64 {
+	if x != nil {
+		return x.UID
+	}
+	return 0
+}
+
+func (x *ReactionRequest) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+type ReactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomID        int64                  `protobuf:"varint,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionResponse) Reset() {
+	*x = ReactionResponse{}
+	mi := &file_message_message_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionResponse) ProtoMessage() {}
+
+func (x *ReactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_message_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionResponse.ProtoReflect.Descriptor instead.
+func (*ReactionResponse) Descriptor() ([]byte, []int) {
+	return file_message_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReactionResponse) GetRoomID() int64 {
+	if x != nil {
+		return x.RoomID
+	}
+	return 0
+}
+
+func (x *ReactionResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -330,7 +1006,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_message_message_proto_msgTypes[5]
+	mi := &file_message_message_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -342,7 +1018,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_message_message_proto_msgTypes[5]
+	mi := &file_message_message_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -355,39 +1031,107 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_message_message_proto_rawDescGZIP(), []int{5}
+	return file_message_message_proto_rawDescGZIP(), []int{16}
 }
 
 var File_message_message_proto protoreflect.FileDescriptor
 
 const file_message_message_proto_rawDesc = "" +
 	"\n" +
-	"\x15message/message.proto\x12\x05msgpb\x1a\x1fgoogle/protobuf/timestamp.proto\"_\n" +
+	"\x15message/message.proto\x12\x05msgpb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x01\n" +
 	"\vSendRequest\x12\x16\n" +
 	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x12\x10\n" +
 	"\x03UID\x18\x02 \x01(\x03R\x03UID\x12\x12\n" +
 	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
-	"\x04text\x18\x04 \x01(\tR\x04text\"X\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\x12\x1a\n" +
+	"\bclientID\x18\x05 \x01(\tR\bclientID\x12\x18\n" +
+	"\areplyTo\x18\x06 \x01(\x03R\areplyTo\"v\n" +
 	"\fSendResponse\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\x03R\x02ID\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xa5\x01\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\"\xc6\x04\n" +
 	"\aMessage\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\x03R\x02ID\x12\x16\n" +
 	"\x06roomID\x18\x02 \x01(\x03R\x06roomID\x12\x10\n" +
 	"\x03UID\x18\x03 \x01(\x03R\x03UID\x12\x12\n" +
 	"\x04type\x18\x04 \x01(\tR\x04type\x12\x12\n" +
 	"\x04text\x18\x05 \x01(\tR\x04text\x128\n" +
-	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"<\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1a\n" +
+	"\bclientID\x18\a \x01(\tR\bclientID\x126\n" +
+	"\beditedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x128\n" +
+	"\tdeletedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12;\n" +
+	"\treactions\x18\n" +
+	" \x03(\v2\x1d.msgpb.Message.ReactionsEntryR\treactions\x12\x18\n" +
+	"\areplyTo\x18\v \x01(\x03R\areplyTo\x12\x1e\n" +
+	"\n" +
+	"threadRoot\x18\f \x01(\x03R\n" +
+	"threadRoot\x12\x1e\n" +
+	"\n" +
+	"replyCount\x18\r \x01(\x03R\n" +
+	"replyCount\x12<\n" +
+	"\vlastReplyAt\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vlastReplyAt\x1a<\n" +
+	"\x0eReactionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"<\n" +
 	"\n" +
 	"GetRequest\x12\x16\n" +
 	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x12\x16\n" +
-	"\x06lastID\x18\x02 \x01(\x03R\x06lastID\"9\n" +
+	"\x06lastID\x18\x02 \x01(\x03R\x06lastID\"b\n" +
+	"\x10GetThreadRequest\x12\x16\n" +
+	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x12\x1e\n" +
+	"\n" +
+	"threadRoot\x18\x02 \x01(\x03R\n" +
+	"threadRoot\x12\x16\n" +
+	"\x06lastID\x18\x03 \x01(\x03R\x06lastID\"9\n" +
 	"\vGetResponse\x12*\n" +
-	"\bmessages\x18\x01 \x03(\v2\x0e.msgpb.MessageR\bmessages\"\a\n" +
-	"\x05Empty2\x93\x01\n" +
+	"\bmessages\x18\x01 \x03(\v2\x0e.msgpb.MessageR\bmessages\"Y\n" +
+	"\x0fMarkReadRequest\x12\x16\n" +
+	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x12\x10\n" +
+	"\x03UID\x18\x02 \x01(\x03R\x03UID\x12\x1c\n" +
+	"\tmessageID\x18\x03 \x01(\x03R\tmessageID\"2\n" +
+	"\x10MarkReadResponse\x12\x1e\n" +
+	"\n" +
+	"lastReadID\x18\x01 \x01(\x03R\n" +
+	"lastReadID\";\n" +
+	"\rUnreadRequest\x12\x10\n" +
+	"\x03UID\x18\x01 \x01(\x03R\x03UID\x12\x18\n" +
+	"\aroomIDs\x18\x02 \x03(\x03R\aroomIDs\"\x86\x01\n" +
+	"\x0eUnreadResponse\x129\n" +
+	"\x06counts\x18\x01 \x03(\v2!.msgpb.UnreadResponse.CountsEntryR\x06counts\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"Q\n" +
+	"\vEditRequest\x12\x1c\n" +
+	"\tmessageID\x18\x01 \x01(\x03R\tmessageID\x12\x10\n" +
+	"\x03UID\x18\x02 \x01(\x03R\x03UID\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"^\n" +
+	"\fEditResponse\x12\x16\n" +
+	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x126\n" +
+	"\beditedAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\"?\n" +
+	"\rDeleteRequest\x12\x1c\n" +
+	"\tmessageID\x18\x01 \x01(\x03R\tmessageID\x12\x10\n" +
+	"\x03UID\x18\x02 \x01(\x03R\x03UID\"b\n" +
+	"\x0eDeleteResponse\x12\x16\n" +
+	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x128\n" +
+	"\tdeletedAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"W\n" +
+	"\x0fReactionRequest\x12\x1c\n" +
+	"\tmessageID\x18\x01 \x01(\x03R\tmessageID\x12\x10\n" +
+	"\x03UID\x18\x02 \x01(\x03R\x03UID\x12\x14\n" +
+	"\x05emoji\x18\x03 \x01(\tR\x05emoji\"@\n" +
+	"\x10ReactionResponse\x12\x16\n" +
+	"\x06roomID\x18\x01 \x01(\x03R\x06roomID\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\a\n" +
+	"\x05Empty2\xac\x04\n" +
 	"\x0eMessageService\x12/\n" +
 	"\x04Send\x12\x12.msgpb.SendRequest\x1a\x13.msgpb.SendResponse\x12,\n" +
-	"\x03Get\x12\x11.msgpb.GetRequest\x1a\x12.msgpb.GetResponse\x12\"\n" +
+	"\x03Get\x12\x11.msgpb.GetRequest\x1a\x12.msgpb.GetResponse\x128\n" +
+	"\tGetThread\x12\x17.msgpb.GetThreadRequest\x1a\x12.msgpb.GetResponse\x12;\n" +
+	"\bMarkRead\x12\x16.msgpb.MarkReadRequest\x1a\x17.msgpb.MarkReadResponse\x125\n" +
+	"\x06Unread\x12\x14.msgpb.UnreadRequest\x1a\x15.msgpb.UnreadResponse\x12/\n" +
+	"\x04Edit\x12\x12.msgpb.EditRequest\x1a\x13.msgpb.EditResponse\x125\n" +
+	"\x06Delete\x12\x14.msgpb.DeleteRequest\x1a\x15.msgpb.DeleteResponse\x12>\n" +
+	"\vAddReaction\x12\x16.msgpb.ReactionRequest\x1a\x17.msgpb.ReactionResponse\x12A\n" +
+	"\x0eRemoveReaction\x12\x16.msgpb.ReactionRequest\x1a\x17.msgpb.ReactionResponse\x12\"\n" +
 	"\x04Ping\x12\f.msgpb.Empty\x1a\f.msgpb.EmptyB+Z)github.com/P3rCh1/chat-server/proto/msgpbb\x06proto3"
 
 var (
@@ -402,31 +1146,65 @@ func file_message_message_proto_rawDescGZIP() []byte {
 	return file_message_message_proto_rawDescData
 }
 
-var file_message_message_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_message_message_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_message_message_proto_goTypes = []any{
 	(*SendRequest)(nil),           // 0: msgpb.SendRequest
 	(*SendResponse)(nil),          // 1: msgpb.SendResponse
 	(*Message)(nil),               // 2: msgpb.Message
 	(*GetRequest)(nil),            // 3: msgpb.GetRequest
-	(*GetResponse)(nil),           // 4: msgpb.GetResponse
-	(*Empty)(nil),                 // 5: msgpb.Empty
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*GetThreadRequest)(nil),      // 4: msgpb.GetThreadRequest
+	(*GetResponse)(nil),           // 5: msgpb.GetResponse
+	(*MarkReadRequest)(nil),       // 6: msgpb.MarkReadRequest
+	(*MarkReadResponse)(nil),      // 7: msgpb.MarkReadResponse
+	(*UnreadRequest)(nil),         // 8: msgpb.UnreadRequest
+	(*UnreadResponse)(nil),        // 9: msgpb.UnreadResponse
+	(*EditRequest)(nil),           // 10: msgpb.EditRequest
+	(*EditResponse)(nil),          // 11: msgpb.EditResponse
+	(*DeleteRequest)(nil),         // 12: msgpb.DeleteRequest
+	(*DeleteResponse)(nil),        // 13: msgpb.DeleteResponse
+	(*ReactionRequest)(nil),       // 14: msgpb.ReactionRequest
+	(*ReactionResponse)(nil),      // 15: msgpb.ReactionResponse
+	(*Empty)(nil),                 // 16: msgpb.Empty
+	nil,                           // 17: msgpb.Message.ReactionsEntry
+	nil,                           // 18: msgpb.UnreadResponse.CountsEntry
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
 }
 var file_message_message_proto_depIdxs = []int32{
-	6, // 0: msgpb.SendResponse.timestamp:type_name -> google.protobuf.Timestamp
-	6, // 1: msgpb.Message.timestamp:type_name -> google.protobuf.Timestamp
-	2, // 2: msgpb.GetResponse.messages:type_name -> msgpb.Message
-	0, // 3: msgpb.MessageService.Send:input_type -> msgpb.SendRequest
-	3, // 4: msgpb.MessageService.Get:input_type -> msgpb.GetRequest
-	5, // 5: msgpb.MessageService.Ping:input_type -> msgpb.Empty
-	1, // 6: msgpb.MessageService.Send:output_type -> msgpb.SendResponse
-	4, // 7: msgpb.MessageService.Get:output_type -> msgpb.GetResponse
-	5, // 8: msgpb.MessageService.Ping:output_type -> msgpb.Empty
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	19, // 0: msgpb.SendResponse.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 1: msgpb.Message.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 2: msgpb.Message.editedAt:type_name -> google.protobuf.Timestamp
+	19, // 3: msgpb.Message.deletedAt:type_name -> google.protobuf.Timestamp
+	17, // 4: msgpb.Message.reactions:type_name -> msgpb.Message.ReactionsEntry
+	19, // 5: msgpb.Message.lastReplyAt:type_name -> google.protobuf.Timestamp
+	2,  // 6: msgpb.GetResponse.messages:type_name -> msgpb.Message
+	18, // 7: msgpb.UnreadResponse.counts:type_name -> msgpb.UnreadResponse.CountsEntry
+	19, // 8: msgpb.EditResponse.editedAt:type_name -> google.protobuf.Timestamp
+	19, // 9: msgpb.DeleteResponse.deletedAt:type_name -> google.protobuf.Timestamp
+	0,  // 10: msgpb.MessageService.Send:input_type -> msgpb.SendRequest
+	3,  // 11: msgpb.MessageService.Get:input_type -> msgpb.GetRequest
+	4,  // 12: msgpb.MessageService.GetThread:input_type -> msgpb.GetThreadRequest
+	6,  // 13: msgpb.MessageService.MarkRead:input_type -> msgpb.MarkReadRequest
+	8,  // 14: msgpb.MessageService.Unread:input_type -> msgpb.UnreadRequest
+	10, // 15: msgpb.MessageService.Edit:input_type -> msgpb.EditRequest
+	12, // 16: msgpb.MessageService.Delete:input_type -> msgpb.DeleteRequest
+	14, // 17: msgpb.MessageService.AddReaction:input_type -> msgpb.ReactionRequest
+	14, // 18: msgpb.MessageService.RemoveReaction:input_type -> msgpb.ReactionRequest
+	16, // 19: msgpb.MessageService.Ping:input_type -> msgpb.Empty
+	1,  // 20: msgpb.MessageService.Send:output_type -> msgpb.SendResponse
+	5,  // 21: msgpb.MessageService.Get:output_type -> msgpb.GetResponse
+	5,  // 22: msgpb.MessageService.GetThread:output_type -> msgpb.GetResponse
+	7,  // 23: msgpb.MessageService.MarkRead:output_type -> msgpb.MarkReadResponse
+	9,  // 24: msgpb.MessageService.Unread:output_type -> msgpb.UnreadResponse
+	11, // 25: msgpb.MessageService.Edit:output_type -> msgpb.EditResponse
+	13, // 26: msgpb.MessageService.Delete:output_type -> msgpb.DeleteResponse
+	15, // 27: msgpb.MessageService.AddReaction:output_type -> msgpb.ReactionResponse
+	15, // 28: msgpb.MessageService.RemoveReaction:output_type -> msgpb.ReactionResponse
+	16, // 29: msgpb.MessageService.Ping:output_type -> msgpb.Empty
+	20, // [20:30] is the sub-list for method output_type
+	10, // [10:20] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_message_message_proto_init() }
@@ -440,7 +1218,7 @@ func file_message_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_message_message_proto_rawDesc), len(file_message_message_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},